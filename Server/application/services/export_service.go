@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+)
+
+// ExportService abre un find para exportarlo. El comando se analiza igual
+// que en /analyze (en modo validate) y, si el rol de la petición lo
+// permite, se devuelve un iterador sobre todos sus documentos, sin el límite
+// de resultados de los cursores.
+type ExportService struct {
+	analyzer *MongoAnalyzerService
+	streamer interfaces.DocumentStreamer
+	auth     interfaces.Authorizer
+}
+
+// NewExportService crea el servicio. auth es opcional, como en
+// NewMongoAnalyzerService.
+func NewExportService(analyzer *MongoAnalyzerService, streamer interfaces.DocumentStreamer, auth interfaces.Authorizer) *ExportService {
+	return &ExportService{analyzer: analyzer, streamer: streamer, auth: auth}
+}
+
+// Open analiza input y abre el iterador. Si el comando no es válido, no es
+// un find o el rol no lo permite, el resultado lo explica y el iterador es
+// nil. Quien recibe el iterador debe cerrarlo.
+func (s *ExportService) Open(ctx context.Context, input string, options entities.AnalysisOptions) (*entities.AnalysisResult, interfaces.DocumentIterator, error) {
+	locale := i18n.MatchLocale(options.Locale)
+
+	options.Mode = entities.MODE_VALIDATE
+	result, err := s.analyzer.Analyze(ctx, input, options)
+	if err != nil || !result.IsValid {
+		return result, nil, err
+	}
+
+	command := result.Command
+	if command.Type != entities.FIND {
+		result.IsValid = false
+		result.Errors = []string{i18n.Translate(locale, i18n.ExportUnsupportedCommand, command.Type)}
+		return result, nil, nil
+	}
+
+	if s.auth != nil {
+		if denied := s.auth.Authorize(ctx, command); denied != nil {
+			result.Authorization = denied
+			return result, nil, nil
+		}
+	}
+
+	iterator, err := s.streamer.Stream(ctx, command)
+	if err != nil {
+		return result, nil, err
+	}
+	return result, iterator, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+)
+
+// ImportService importa los documentos de un archivo en una colección.
+// Cada documento pasa por el validador como si fuera un insertOne (o un
+// replaceOne si se identifica por un campo); los que no lo superan se
+// informan como errores de su fila y no se escriben. El resto se escribe
+// por lotes.
+type ImportService struct {
+	validator interfaces.Validator
+	writer    interfaces.BatchWriter
+	auth      interfaces.Authorizer
+}
+
+// NewImportService crea el servicio. auth es opcional, como en
+// NewMongoAnalyzerService.
+func NewImportService(validator interfaces.Validator, writer interfaces.BatchWriter, auth interfaces.Authorizer) *ImportService {
+	return &ImportService{validator: validator, writer: writer, auth: auth}
+}
+
+// pendingWrite es un documento válido que espera a que se complete su lote.
+type pendingWrite struct {
+	row   int
+	write entities.BatchWrite
+}
+
+// Import lee rows hasta el final y devuelve los totales. progress, si no es
+// nil, se llama después de cada lote. Si el espacio de nombres no es válido
+// o el rol no permite escribir en él, no se lee ninguna fila y el error es
+// un *entities.Diagnostic o un *entities.AuthorizationError.
+func (s *ImportService) Import(ctx context.Context, rows interfaces.RowReader, options entities.ImportOptions, progress func(entities.ImportProgress)) (entities.ImportProgress, error) {
+	locale := i18n.MatchLocale(options.Locale)
+	var total entities.ImportProgress
+
+	if err := s.authorize(ctx, options); err != nil {
+		return total, err
+	}
+
+	var batch []pendingWrite
+	var failed []entities.ImportRowError
+	fail := func(row int, err error) {
+		total.Failed++
+		failed = append(failed, entities.ImportRowError{Row: row, Message: i18n.LocalizeError(err, locale)})
+	}
+
+	flush := func() error {
+		if len(batch) == 0 && len(failed) == 0 {
+			return nil
+		}
+		if len(batch) > 0 {
+			writes := make([]entities.BatchWrite, len(batch))
+			for i, pending := range batch {
+				writes[i] = pending.write
+			}
+			outcomes, err := s.writer.WriteBatch(ctx, options.Database, options.Collection, writes)
+			if err != nil {
+				return err
+			}
+			for i, outcome := range outcomes {
+				switch {
+				case outcome.Err != nil:
+					fail(batch[i].row, outcome.Err)
+				case outcome.Updated:
+					total.Updated++
+				default:
+					total.Inserted++
+				}
+			}
+			batch = batch[:0]
+		}
+
+		if progress != nil {
+			update := total
+			update.Errors = failed
+			progress(update)
+		}
+		failed = nil
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return total, flushErr
+			}
+			return total, err
+		}
+
+		total.Rows++
+		if row.Err != nil {
+			fail(row.Row, row.Err)
+			continue
+		}
+
+		command, err := importCommand(options, row.Document)
+		if err == nil {
+			err = s.validator.ValidateSemantics(ctx, command)
+		}
+		if err != nil {
+			fail(row.Row, err)
+			continue
+		}
+
+		batch = append(batch, pendingWrite{row: row.Row, write: entities.BatchWrite{Filter: command.Filter, Document: command.Document}})
+		if len(batch) >= options.BatchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+
+	return total, flush()
+}
+
+// authorize comprueba el espacio de nombres y que el rol de la petición
+// puede insertar (y, con UpsertBy, reemplazar) documentos en él.
+func (s *ImportService) authorize(ctx context.Context, options entities.ImportOptions) error {
+	namespace := []*entities.MongoCommand{
+		{Type: entities.USE_DATABASE, Database: options.Database, IsValid: true},
+		{Type: entities.CREATE_COLLECTION, Database: options.Database, Collection: options.Collection, IsValid: true},
+	}
+	for _, command := range namespace {
+		if err := s.validator.ValidateSemantics(ctx, command); err != nil {
+			return err
+		}
+	}
+
+	if s.auth == nil {
+		return nil
+	}
+	types := []entities.CommandType{entities.INSERT_ONE}
+	if options.UpsertBy != "" {
+		types = append(types, entities.REPLACE_ONE)
+	}
+	for _, commandType := range types {
+		command := &entities.MongoCommand{Type: commandType, Database: options.Database, Collection: options.Collection}
+		if denied := s.auth.Authorize(ctx, command); denied != nil {
+			return denied
+		}
+	}
+	return nil
+}
+
+// importCommand construye el comando con el que se valida un documento:
+// insertOne o, si se identifica por un campo, replaceOne con ese campo como
+// filtro.
+func importCommand(options entities.ImportOptions, document map[string]interface{}) (*entities.MongoCommand, error) {
+	command := &entities.MongoCommand{
+		Type:       entities.INSERT_ONE,
+		Database:   options.Database,
+		Collection: options.Collection,
+		Document:   document,
+		IsValid:    true,
+	}
+	if options.UpsertBy == "" {
+		return command, nil
+	}
+
+	value, ok := fieldValue(document, options.UpsertBy)
+	if !ok {
+		return nil, entities.NewDiagnostic(i18n.ImportMissingUpsertField, options.UpsertBy)
+	}
+	command.Type = entities.REPLACE_ONE
+	command.Filter = map[string]interface{}{options.UpsertBy: value}
+	return command, nil
+}
+
+// fieldValue devuelve el valor de un campo, que puede estar dentro de
+// subdocumentos (direccion.codigo).
+func fieldValue(document map[string]interface{}, path string) (interface{}, bool) {
+	segments := strings.Split(path, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := document[segment].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		document = next
+	}
+	value, ok := document[segments[len(segments)-1]]
+	return value, ok
+}
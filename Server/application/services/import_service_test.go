@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mongo-analyzer/application/services"
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/infrastructure/executor/memory"
+	"mongo-analyzer/infrastructure/importer"
+	"mongo-analyzer/infrastructure/validator"
+)
+
+func TestImportReportsBadRowsAndUpserts(t *testing.T) {
+	executor := memory.NewMemoryExecutor(nil)
+	imports := services.NewImportService(validator.NewMongoValidator(nil, executor, nil, nil), executor, nil)
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+	options := entities.ImportOptions{Database: "escuela", Collection: "alumnos", BatchSize: 2}
+
+	csv := "codigo,nombre,edad,dir.ciudad,cp\n" +
+		"A1,Ana,20,Lima,01234\n" +
+		"A2,Luis,diecisiete,,\n" +
+		"A3,Eva,30,,\n"
+	rows, err := importer.NewCSVReader(strings.NewReader(csv), importer.Options{Types: map[string]importer.FieldType{"edad": importer.TypeInt}, Infer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batches int
+	total, err := imports.Import(ctx, rows, options, func(entities.ImportProgress) { batches++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Rows != 3 || total.Inserted != 2 || total.Failed != 1 || batches != 1 {
+		t.Fatalf("importación = %+v en %d lotes", total, batches)
+	}
+
+	json := importer.NewJSONReader(strings.NewReader(`[{"codigo": "A1", "edad": 21}, {"codigo": "A9"}, {"nombre": "sin código"}]`))
+	options.UpsertBy = "codigo"
+	total, err = imports.Import(ctx, json, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Inserted != 1 || total.Updated != 1 || total.Failed != 1 {
+		t.Fatalf("upsert = %+v", total)
+	}
+
+	found := documents(t, executor, ctx, map[string]interface{}{"codigo": "A1"})
+	if len(found) != 1 || found[0]["edad"] != 21 || found[0]["nombre"] != nil {
+		t.Fatalf("A1 tras el upsert = %v", found)
+	}
+}
+
+func documents(t *testing.T, executor *memory.MemoryExecutor, ctx context.Context, filter map[string]interface{}) []map[string]interface{} {
+	t.Helper()
+
+	iterator, err := executor.Stream(ctx, &entities.MongoCommand{Type: entities.FIND, Database: "escuela", Collection: "alumnos", Filter: filter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iterator.Close(ctx)
+	found, err := iterator.Next(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
@@ -1,10 +1,14 @@
 package services
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"sort"
 	"strings"
+	"time"
 
 	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
 	"mongo-analyzer/domain/interfaces"
 )
 
@@ -13,141 +17,508 @@ type MongoAnalyzerService struct {
 	parser    interfaces.Parser
 	validator interfaces.Validator
 	executor  interfaces.MongoExecutor
+	suggester interfaces.Suggester
+	policy    interfaces.CommandPolicy
+	auth      interfaces.Authorizer
 }
 
+// NewMongoAnalyzerService crea el servicio. policy es opcional: si se
+// indica, decide antes de cada ejecución si el comando puede ejecutarse.
+// auth también es opcional: si se indica, comprueba el rol de la petición
+// antes de validar el comando. Simular y ejecutar exigen que el rol permita
+// el comando; validar exige que pueda leer su espacio de nombres.
 func NewMongoAnalyzerService(
 	lexer interfaces.Lexer,
 	parser interfaces.Parser,
 	validator interfaces.Validator,
 	executor interfaces.MongoExecutor,
+	suggester interfaces.Suggester,
+	policy interfaces.CommandPolicy,
+	auth interfaces.Authorizer,
 ) *MongoAnalyzerService {
 	return &MongoAnalyzerService{
 		lexer:     lexer,
 		parser:    parser,
 		validator: validator,
 		executor:  executor,
+		suggester: suggester,
+		policy:    policy,
+		auth:      auth,
 	}
 }
 
-func (s *MongoAnalyzerService) Analyze(input string) (*entities.AnalysisResult, error) {
+func (s *MongoAnalyzerService) Analyze(ctx context.Context, input string, options entities.AnalysisOptions) (*entities.AnalysisResult, error) {
+	locale := i18n.MatchLocale(options.Locale)
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	var trace *entities.AnalysisTrace
+	if options.Trace {
+		trace = &entities.AnalysisTrace{}
+		ctx = entities.ContextWithTrace(ctx, trace)
+	}
+
+	var derivation *entities.DerivationLog
+	if options.Derivation {
+		derivation = &entities.DerivationLog{}
+		ctx = entities.ContextWithDerivation(ctx, derivation)
+	}
+
 	// Fase 1: Análisis Léxico
+	started := time.Now()
 	tokens, err := s.lexer.Tokenize(input)
+	trace.RecordPhase("lexing", started)
 	if err != nil {
 		return &entities.AnalysisResult{
 			IsValid:      false,
-			Errors:       []string{"Error léxico: " + err.Error()},
-			SuggestedFix: s.generateLexicalFix(input, err),
+			Errors:       []string{i18n.Translate(locale, i18n.AnalyzeLexicalError, err)},
+			SuggestedFix: s.generateLexicalFix(input, err).Localize(locale),
+			Trace:        trace,
+			Derivation:   derivation,
 		}, nil
 	}
+	if trace != nil {
+		trace.Tokens = tokens
+	}
+
+	suggestions := s.suggestFromTokens(tokens)
 
-	
-	command, err := s.parser.Parse(tokens)
+	started = time.Now()
+	command, err := s.parser.Parse(ctx, tokens)
+	trace.RecordPhase("parsing", started)
 	if err != nil {
+		suggestions = append(suggestions, s.suggestNames(ctx, tokens, nil)...)
 		return &entities.AnalysisResult{
 			IsValid:      false,
-			Errors:       []string{"Error sintáctico: " + err.Error()},
+			Errors:       []string{i18n.Translate(locale, i18n.AnalyzeSyntaxError, err)},
 			TokenCount:   len(tokens) - 1, // Excluir EOF
-			SuggestedFix: s.generateSyntacticFix(input, err),
+			SuggestedFix: s.preferSuggestion(suggestions, s.generateSyntacticFix(input, err)).Localize(locale),
+			Suggestions:  localizeAll(suggestions, locale),
+			Trace:        trace,
+			Derivation:   derivation,
 		}, nil
 	}
+	if trace != nil {
+		trace.AST = command.AST
+	}
+	command.Source = input
+
+	// Los comandos sobre colecciones se resuelven en la base de datos actual,
+	// de modo que la validación conozca el espacio de nombres completo.
+	if command.Database == "" && s.executor != nil {
+		command.Database = s.executor.CurrentDatabase(ctx)
+	}
+
+	// Las colecciones y los campos se comparan con el catálogo, que está en
+	// caché; los nombres que existen no generan sugerencias.
+	suggestions = append(suggestions, s.suggestNames(ctx, tokens, command)...)
 
 	if !command.IsValid {
 		return &entities.AnalysisResult{
 			Command:      command,
 			IsValid:      false,
-			Errors:       command.Errors,
+			Errors:       localizeAll(command.Errors, locale),
 			TokenCount:   command.TokenCount,
-			SuggestedFix: s.generateSyntacticFixFromCommand(command),
+			SuggestedFix: s.preferSuggestion(suggestions, s.generateSyntacticFixFromCommand(command)).Localize(locale),
+			Suggestions:  localizeAll(suggestions, locale),
+			Trace:        trace,
+			Derivation:   derivation,
 		}, nil
 	}
 
-	
-	if err := s.validator.ValidateSemantics(command); err != nil {
+	mode := options.Mode
+	if mode == "" {
+		mode = entities.MODE_EXECUTE
+	}
+
+	// Validar también consulta el servidor: el catálogo, el $jsonSchema de
+	// la colección y muestras de documentos para el lint. Por eso el rol se
+	// comprueba antes: simular y ejecutar requieren que permita el comando,
+	// y validar que permita leer su espacio de nombres.
+	if s.auth != nil {
+		started = time.Now()
+		denied := s.auth.Authorize(ctx, permissionProbe(command, mode))
+		trace.RecordPhase("permissions", started)
+		if denied != nil {
+			return &entities.AnalysisResult{
+				Command:       command,
+				IsValid:       false,
+				Mode:          mode,
+				TokenCount:    command.TokenCount,
+				Suggestions:   localizeAll(suggestions, locale),
+				Authorization: denied,
+				Trace:         trace,
+				Derivation:    derivation,
+			}, nil
+		}
+	}
+
+	started = time.Now()
+	err = s.validator.ValidateSemantics(ctx, command)
+	trace.RecordPhase("validating", started)
+	if err != nil {
 		return &entities.AnalysisResult{
 			Command:      command,
 			IsValid:      false,
-			Errors:       []string{"Error semántico: " + err.Error()},
+			Errors:       []string{i18n.Translate(locale, i18n.AnalyzeSemanticError, err)},
 			TokenCount:   command.TokenCount,
-			SuggestedFix: s.generateSemanticFix(command, err),
+			SuggestedFix: s.preferSuggestion(suggestions, s.generateSemanticFix(command, err)).Localize(locale),
+			Suggestions:  localizeAll(suggestions, locale),
+			Trace:        trace,
+			Derivation:   derivation,
+		}, nil
+	}
+
+	started = time.Now()
+	findings := s.validator.Lint(ctx, command, options.Lint)
+	trace.RecordPhase("linting", started)
+
+	// Las reglas con nivel error bloquean el comando igual que un error
+	// semántico.
+	if blocking := lintErrors(findings, locale); len(blocking) > 0 {
+		return &entities.AnalysisResult{
+			Command:      command,
+			IsValid:      false,
+			Errors:       blocking,
+			TokenCount:   command.TokenCount,
+			SuggestedFix: i18n.Translate(locale, i18n.FixLintRule, firstLintError(findings).Rule),
+			Suggestions:  localizeAll(suggestions, locale),
+			Trace:        trace,
+			Derivation:   derivation,
+			Lint:         localizeFindings(findings, locale),
 		}, nil
 	}
 
-	
 	var executionResult interface{}
 	var executionError error
 
-	if s.executor != nil {
-		executionResult, executionError = s.executor.Execute(command)
+	// La política solo decide sobre la ejecución real: validar y simular no
+	// modifican datos.
+	var decision *entities.PolicyDecision
+	if mode == entities.MODE_EXECUTE && s.policy != nil {
+		started = time.Now()
+		decision = s.policy.Authorize(ctx, command, options.Confirmation)
+		trace.RecordPhase("authorizing", started)
+	}
+
+	if s.executor != nil && (decision == nil || decision.Allowed) {
+		started = time.Now()
+		switch mode {
+		case entities.MODE_DRY_RUN:
+			executionResult, executionError = s.executor.DryRun(ctx, command)
+			trace.RecordPhase("executing", started)
+		case entities.MODE_EXECUTE:
+			executionResult, executionError = s.executor.Execute(ctx, command)
+			trace.RecordPhase("executing", started)
+		}
 	}
 
 	return &entities.AnalysisResult{
 		Command:         command,
 		IsValid:         true,
+		Mode:            mode,
 		TokenCount:      command.TokenCount,
-		ExecutionResult: executionResult,
-		ExecutionError:  executionError,
+		Suggestions:     localizeAll(suggestions, locale),
+		Lint:            localizeFindings(findings, locale),
+		ExecutionResult: localizeResult(executionResult, locale),
+		ExecutionError:  contextError(ctx, executionError),
+		Policy:          decision,
+		Trace:           trace,
+		Derivation:      derivation,
 	}, nil
 }
 
-
-func (s *MongoAnalyzerService) generateLexicalFix(_ string, err error) string {
+func (s *MongoAnalyzerService) generateLexicalFix(_ string, err error) *entities.Diagnostic {
 	// Sugerencias básicas para errores léxicos
-	if strings.Contains(err.Error(), "token inválido") {
-		return "Verifica caracteres especiales. Ejemplo correcto: db.usuarios.find()"
+	if diagnosticCode(err) == i18n.LexInvalidToken {
+		return entities.NewDiagnostic(i18n.FixInvalidCharacters)
 	}
-	return "Revisa la sintaxis del comando"
+	return entities.NewDiagnostic(i18n.FixCheckSyntax)
 }
 
+func (s *MongoAnalyzerService) generateSyntacticFix(_ string, err error) *entities.Diagnostic {
+	switch diagnosticCode(err) {
+	case i18n.ParseExpectedDotAfterDB, i18n.ParseExpectedDotAfterCollection:
+		return entities.NewDiagnostic(i18n.FixAddDot)
+	case i18n.ParseExpectedLeftParen:
+		return entities.NewDiagnostic(i18n.FixAddParens)
+	case i18n.ParseExpectedRightParen, i18n.ParseExpectedRightParenCollection,
+		i18n.ParseExpectedRightParenDocument, i18n.ParseExpectedRightParenFilter:
+		return entities.NewDiagnostic(i18n.FixCloseParens)
+	case i18n.ParseExpectedLeftBrace:
+		return entities.NewDiagnostic(i18n.FixUseBraces)
+	}
 
-func (s *MongoAnalyzerService) generateSyntacticFix(_ string, err error) string {
-	errorMsg := err.Error()
-	
-	if strings.Contains(errorMsg, "Se esperaba '.'") {
-		return "Agrega un punto después de 'db': db.nombreColeccion.funcion()"
+	return entities.NewDiagnostic(i18n.FixCheckMongoSyntax)
+}
+
+func (s *MongoAnalyzerService) generateSyntacticFixFromCommand(command *entities.MongoCommand) *entities.Diagnostic {
+	if len(command.Errors) > 0 {
+		return s.generateSyntacticFix("", command.Errors[0])
 	}
-	if strings.Contains(errorMsg, "Se esperaba '('") {
-		return "Agrega paréntesis después de la función: funcion()"
+	return entities.NewDiagnostic(i18n.FixSyntaxIncorrect)
+}
+
+func (s *MongoAnalyzerService) generateSemanticFix(_ *entities.MongoCommand, err error) *entities.Diagnostic {
+	switch diagnosticCode(err) {
+	case i18n.SemDatabaseNameEmpty, i18n.SemDatabaseNameChars, i18n.SemDatabaseNameTooLong, i18n.SemDatabaseNameNull, i18n.SemDatabaseNameCaseClash:
+		return entities.NewDiagnostic(i18n.FixDatabaseName)
+	case i18n.SemCollectionNameEmpty, i18n.SemCollectionNameDollar, i18n.SemCollectionNameNull, i18n.SemCollectionNameReserved, i18n.SemNamespaceTooLong:
+		return entities.NewDiagnostic(i18n.FixCollectionName)
+	case i18n.SemFieldNameNull, i18n.SemFieldNameEmptySegment, i18n.SemDocumentTooDeep, i18n.SemDocumentTooLarge:
+		return entities.NewDiagnostic(i18n.FixDocumentStructure)
+	case i18n.SemInsertEmpty:
+		return entities.NewDiagnostic(i18n.FixInsertDocument)
+	case i18n.SemUpdateFilterEmpty, i18n.SemDeleteFilterEmpty:
+		return entities.NewDiagnostic(i18n.FixUpdateFilter)
+	case i18n.SemUpdateNoOperator, i18n.SemUpdateEmpty, i18n.SemUnknownUpdateOperator, i18n.SemUpdateMixedFields,
+		i18n.SemUpdateOperatorEmpty, i18n.SemUnknownUpdateModifier, i18n.SemUpdateModifierWithoutEach:
+		return entities.NewDiagnostic(i18n.FixUpdateOperator)
+	case i18n.SemReplaceFilterEmpty, i18n.SemReplaceOperator:
+		return entities.NewDiagnostic(i18n.FixReplaceDocument)
+	case i18n.SemSchemaType, i18n.SemSchemaRequired, i18n.SemSchemaAdditional, i18n.SemSchemaEnum,
+		i18n.SemSchemaMinimum, i18n.SemSchemaMaximum, i18n.SemSchemaMultipleOf, i18n.SemSchemaMinLength,
+		i18n.SemSchemaMaxLength, i18n.SemSchemaPattern, i18n.SemSchemaMinItems, i18n.SemSchemaMaxItems,
+		i18n.SemSchemaUniqueItems, i18n.SemSchemaMinProperties, i18n.SemSchemaMaxProperties,
+		i18n.SemSchemaAnyOf, i18n.SemSchemaOneOf, i18n.SemSchemaNot:
+		return entities.NewDiagnostic(i18n.FixJSONSchema)
+	case i18n.SemUpdatePathConflict:
+		return entities.NewDiagnostic(i18n.FixUpdatePathConflict)
+	case i18n.SemUpdatePipelineEmpty, i18n.SemUpdatePipelineStageShape, i18n.SemUpdatePipelineStage:
+		return entities.NewDiagnostic(i18n.FixUpdatePipeline)
+	case i18n.SemUnknownQueryOperator, i18n.SemOperatorNeedsField, i18n.SemLogicalOperatorInField,
+		i18n.SemMixedOperatorsAndFields, i18n.SemOptionsWithoutRegex, i18n.SemOperandType:
+		return entities.NewDiagnostic(i18n.FixQueryOperator)
 	}
-	if strings.Contains(errorMsg, "Se esperaba ')'") {
-		return "Cierra los paréntesis: funcion(...)"
+
+	return entities.NewDiagnostic(i18n.FixCheckLogic)
+}
+
+// suggestFromTokens busca funciones y operadores mal escritos directamente
+// en los tokens, de modo que también funciona cuando el parser no llegó a
+// construir el comando. Solo compara con listas fijas: no consulta el
+// servidor.
+func (s *MongoAnalyzerService) suggestFromTokens(tokens []*entities.Token) []*entities.Diagnostic {
+	if s.suggester == nil {
+		return nil
 	}
-	if strings.Contains(errorMsg, "Se esperaba '{'") {
-		return "Usa llaves para objetos: { campo: valor }"
+
+	var suggestions []*entities.Diagnostic
+	for i, token := range tokens {
+		if token.Type != entities.IDENTIFIER {
+			continue
+		}
+
+		var suggestion string
+		var ok bool
+		switch {
+		case i >= 1 && tokens[i-1].Type == entities.DOLLAR_SIGN:
+			// $identificador como clave: operador
+			if i+1 < len(tokens) && tokens[i+1].Type == entities.COLON {
+				if suggestion, ok = s.suggester.SuggestOperator(token.Value); ok {
+					suggestions = append(suggestions, didYouMean("$"+token.Value, suggestion))
+				}
+			}
+			continue
+		case i >= 4 && tokens[i-1].Type == entities.DOT && tokens[i-3].Type == entities.DOT && tokens[i-4].Type == entities.DB:
+			// db.coleccion.funcion
+			suggestion, ok = s.suggester.SuggestFunction(token.Value)
+		case isCall(tokens, i):
+			// db.funcion(...)
+			suggestion, ok = s.suggester.SuggestFunction(token.Value)
+		}
+
+		if ok {
+			suggestions = append(suggestions, didYouMean(token.Value, suggestion))
+		}
 	}
-	
-	return "Revisa la sintaxis del comando MongoDB"
+
+	return suggestions
 }
 
+// suggestNames sugiere colecciones (db.coleccion) que no existen y, si la
+// colección del comando existe, campos del filtro que no aparecen en ella.
+// command puede ser nil si el parser no llegó a construirlo.
+func (s *MongoAnalyzerService) suggestNames(ctx context.Context, tokens []*entities.Token, command *entities.MongoCommand) []*entities.Diagnostic {
+	if s.suggester == nil {
+		return nil
+	}
 
-func (s *MongoAnalyzerService) generateSyntacticFixFromCommand(command *entities.MongoCommand) string {
-	if len(command.Errors) > 0 {
-		
-		return s.generateSyntacticFix("", fmt.Errorf("%s", command.Errors[0]))
-		
-		
+	database := ""
+	if command != nil {
+		database = command.Database
+	} else if s.executor != nil {
+		database = s.executor.CurrentDatabase(ctx)
+	}
+
+	var suggestions []*entities.Diagnostic
+	misspelled := make(map[string]bool)
+	// Sugerir colecciones revela el catálogo de toda la base de datos
+	if s.mayRead(ctx, database, "") {
+		for i, token := range tokens {
+			if token.Type != entities.IDENTIFIER || i < 2 || tokens[i-1].Type != entities.DOT || tokens[i-2].Type != entities.DB || isCall(tokens, i) {
+				continue
+			}
+			if suggestion, ok := s.suggester.SuggestCollection(ctx, token.Value); ok {
+				misspelled[token.Value] = true
+				suggestions = append(suggestions, didYouMean(token.Value, suggestion))
+			}
+		}
+	}
+
+	if command == nil || command.Collection == "" || misspelled[command.Collection] || !s.mayRead(ctx, database, command.Collection) {
+		return suggestions
+	}
+	for _, key := range sortedKeys(command.Filter) {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		field := strings.Split(key, ".")[0]
+		if suggestion, ok := s.suggester.SuggestField(ctx, command.Collection, field); ok {
+			suggestions = append(suggestions, didYouMean(field, suggestion))
+		}
+	}
+
+	return suggestions
+}
+
+// mayRead indica si el rol de la petición puede consultar con find
+// database.collection, o la base de datos completa si collection es "".
+// Las sugerencias salen del catálogo y de muestras de documentos, así que
+// solo se ofrecen sobre lo que el rol podría leer.
+func (s *MongoAnalyzerService) mayRead(ctx context.Context, database, collection string) bool {
+	if s.auth == nil {
+		return true
+	}
+	return s.auth.Authorize(ctx, &entities.MongoCommand{Type: entities.FIND, Database: database, Collection: collection}) == nil
+}
+
+// permissionProbe es el comando cuyo permiso se comprueba en cada modo:
+// el propio comando al simular o ejecutar, y una lectura de su espacio de
+// nombres al validar (use se comprueba tal cual, porque no tiene
+// colección).
+func permissionProbe(command *entities.MongoCommand, mode entities.ExecutionMode) *entities.MongoCommand {
+	if mode != entities.MODE_VALIDATE || command.Type == entities.USE_DATABASE {
+		return command
+	}
+	return &entities.MongoCommand{Type: entities.FIND, Database: command.Database, Collection: command.Collection, IsValid: true}
+}
+
+// isCall indica si el token i es db.funcion(...).
+func isCall(tokens []*entities.Token, i int) bool {
+	return i >= 2 && tokens[i-1].Type == entities.DOT && tokens[i-2].Type == entities.DB &&
+		i+1 < len(tokens) && tokens[i+1].Type == entities.LEFT_PAREN
+}
+
+func sortedKeys(document map[string]interface{}) []string {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// preferSuggestion usa la primera sugerencia concreta en lugar del consejo
+// genérico cuando existe una.
+func (s *MongoAnalyzerService) preferSuggestion(suggestions []*entities.Diagnostic, fallback *entities.Diagnostic) *entities.Diagnostic {
+	if len(suggestions) > 0 {
+		return suggestions[0]
 	}
-	return "Comando sintácticamente incorrecto"
+	return fallback
 }
 
+func didYouMean(original, suggestion string) *entities.Diagnostic {
+	return entities.NewDiagnostic(i18n.SuggestDidYouMean, suggestion, original)
+}
 
-func (s *MongoAnalyzerService) generateSemanticFix(_ *entities.MongoCommand, err error) string {
-	errorMsg := err.Error()
-	
-	if strings.Contains(errorMsg, "nombre de la base de datos") {
-		return "Usa un nombre válido para la base de datos (sin caracteres especiales)"
+// diagnosticCode devuelve el código del diagnóstico más interno de err, o
+// una cadena vacía si err no es un diagnóstico.
+func diagnosticCode(err error) string {
+	if diagnostic, ok := err.(*entities.Diagnostic); ok {
+		return diagnostic.Root().Code
 	}
-	if strings.Contains(errorMsg, "nombre de la colección") {
-		return "Usa un nombre válido para la colección (no puede empezar con '$')"
+	return ""
+}
+
+func localizeAll(diagnostics []*entities.Diagnostic, locale string) []string {
+	if len(diagnostics) == 0 {
+		return nil
 	}
-	if strings.Contains(errorMsg, "documento a insertar") {
-		return "El documento debe tener al menos un campo: { campo: valor }"
+	messages := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		messages[i] = diagnostic.Localize(locale)
 	}
-	if strings.Contains(errorMsg, "filtro de actualización") {
-		return "Especifica un filtro: { campo: valor }"
+	return messages
+}
+
+// localizeResult traduce los mensajes que el executor deja en el resultado.
+func localizeResult(result interface{}, locale string) interface{} {
+	fields, ok := result.(map[string]interface{})
+	if !ok {
+		return result
 	}
-	if strings.Contains(errorMsg, "operador válido") {
-		return "Usa operadores como $set: { $set: { campo: nuevoValor } }"
+	for key, value := range fields {
+		if localizable, ok := value.(i18n.Localizable); ok {
+			fields[key] = localizable.Localize(locale)
+		}
 	}
-	
-	return "Revisa la lógica del comando"
-}
\ No newline at end of file
+	return fields
+}
+
+// contextError reemplaza los errores causados por la cancelación o el
+// vencimiento de ctx por un diagnóstico legible.
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return entities.NewDiagnostic(i18n.ExecTimeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return entities.NewDiagnostic(i18n.ExecCanceled)
+	}
+	return err
+}
+
+// localizeFindings traduce los hallazgos de lint al idioma de la petición.
+func localizeFindings(findings []entities.LintFinding, locale string) []entities.LintResult {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	results := make([]entities.LintResult, len(findings))
+	for i, finding := range findings {
+		results[i] = entities.LintResult{
+			Rule:     finding.Rule,
+			Severity: finding.Severity,
+			Line:     finding.Line,
+			Message:  finding.Diagnostic.Localize(locale),
+		}
+	}
+	return results
+}
+
+// lintErrors devuelve, traducidos, los hallazgos con nivel error.
+func lintErrors(findings []entities.LintFinding, locale string) []string {
+	var errors []string
+	for _, finding := range findings {
+		if finding.Severity == entities.SEVERITY_ERROR {
+			errors = append(errors, i18n.Translate(locale, i18n.AnalyzeLintError, finding.Rule, finding.Diagnostic))
+		}
+	}
+	return errors
+}
+
+func firstLintError(findings []entities.LintFinding) entities.LintFinding {
+	for _, finding := range findings {
+		if finding.Severity == entities.SEVERITY_ERROR {
+			return finding
+		}
+	}
+	return entities.LintFinding{}
+}
@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"mongo-analyzer/application/services"
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/interfaces"
+	"mongo-analyzer/infrastructure/executor/memory"
+	"mongo-analyzer/infrastructure/lexer"
+	"mongo-analyzer/infrastructure/parser"
+	"mongo-analyzer/infrastructure/policy"
+	"mongo-analyzer/infrastructure/suggester"
+	"mongo-analyzer/infrastructure/validator"
+)
+
+// newAnalyzer monta el servicio completo sobre el backend en memoria y
+// devuelve un contexto con una sesión nueva.
+func newAnalyzer(t *testing.T) (*services.MongoAnalyzerService, context.Context) {
+	t.Helper()
+	return newAnalyzerWithAuth(t, nil)
+}
+
+// newAnalyzerWithAuth es newAnalyzer con un autorizador de roles.
+func newAnalyzerWithAuth(t *testing.T, auth interfaces.Authorizer) (*services.MongoAnalyzerService, context.Context) {
+	t.Helper()
+
+	executor := memory.NewMemoryExecutor(nil)
+	inference := validator.NewSchemaInference(executor, validator.DefaultSampleSize, validator.DefaultSchemaTTL)
+	analyzer := services.NewMongoAnalyzerService(
+		lexer.NewMongoLexer(),
+		parser.NewMongoParser(),
+		validator.NewMongoValidator(validator.NewSchemaRegistry(executor), executor, inference, nil),
+		executor,
+		suggester.NewMongoSuggester(executor, inference, validator.DefaultSchemaTTL),
+		policy.NewGuardrailPolicy(policy.DefaultProtectedDatabases, nil),
+		auth,
+	)
+
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+	return analyzer, ctx
+}
+
+// run analiza input y falla si no es válido o si la ejecución da error.
+func run(t *testing.T, analyzer *services.MongoAnalyzerService, ctx context.Context, input string, options entities.AnalysisOptions) map[string]interface{} {
+	t.Helper()
+
+	result, err := analyzer.Analyze(ctx, input, options)
+	if err != nil {
+		t.Fatalf("%s: %v", input, err)
+	}
+	if !result.IsValid {
+		t.Fatalf("%s: inválido: %v", input, result.Errors)
+	}
+	if result.ExecutionError != nil {
+		t.Fatalf("%s: error de ejecución: %v", input, result.ExecutionError)
+	}
+	fields, _ := result.ExecutionResult.(map[string]interface{})
+	return fields
+}
+
+func TestAnalyzeExecutesAgainstMemoryBackend(t *testing.T) {
+	analyzer, ctx := newAnalyzer(t)
+	execute := entities.AnalysisOptions{Mode: entities.MODE_EXECUTE}
+
+	run(t, analyzer, ctx, `use escuela`, execute)
+	if database := entities.SessionFromContext(ctx).Database(); database != "escuela" {
+		t.Fatalf("base de datos de la sesión = %q, se esperaba escuela", database)
+	}
+
+	run(t, analyzer, ctx, `db.alumnos.insertOne({ "_id": 1, "nombre": "Ana", "edad": 20, "cursos": ["bd"] })`, execute)
+	run(t, analyzer, ctx, `db.alumnos.insertOne({ "_id": 2, "nombre": "Luis", "edad": 17 })`, execute)
+
+	found := run(t, analyzer, ctx, `db.alumnos.find({ "edad": { "$gte": 18 } })`, execute)
+	if found["count"] != 1 {
+		t.Fatalf("find devolvió %v documentos, se esperaba 1", found["count"])
+	}
+
+	updated := run(t, analyzer, ctx, `db.alumnos.updateOne({ "_id": 2 }, { "$inc": { "edad": 1 }, "$push": { "cursos": "bd" } })`, execute)
+	if updated["matchedCount"] != int64(1) || updated["modifiedCount"] != int64(1) {
+		t.Fatalf("updateOne = %v", updated)
+	}
+
+	found = run(t, analyzer, ctx, `db.alumnos.find({ "cursos": "bd", "edad": { "$gte": 18 } })`, execute)
+	documents, _ := found["documents"].([]bson.M)
+	if len(documents) != 2 || documents[1]["edad"] != 18 {
+		t.Fatalf("documentos tras updateOne = %v", documents)
+	}
+
+	deleted := run(t, analyzer, ctx, `db.alumnos.deleteOne({ "nombre": "Ana" })`, execute)
+	if deleted["deletedCount"] != int64(1) {
+		t.Fatalf("deleteOne = %v", deleted)
+	}
+
+	inserted, err := analyzer.Analyze(ctx, `db.alumnos.insertOne({ "_id": 2 })`, execute)
+	if err != nil || inserted.ExecutionError == nil {
+		t.Fatalf("insertar un _id repetido debería fallar: %v %v", err, inserted.ExecutionError)
+	}
+}
+
+func TestAnalyzeReportsSyntaxErrorsWithoutExecuting(t *testing.T) {
+	analyzer, ctx := newAnalyzer(t)
+
+	run(t, analyzer, ctx, `use escuela`, entities.AnalysisOptions{})
+	result, err := analyzer.Analyze(ctx, `db.alumnos.insertOne({ "nombre": "Ana" `, entities.AnalysisOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.IsValid || len(result.Errors) == 0 {
+		t.Fatalf("se esperaba un error de sintaxis, IsValid=%v", result.IsValid)
+	}
+
+	found := run(t, analyzer, ctx, `db.alumnos.find({})`, entities.AnalysisOptions{})
+	if found["count"] != 0 {
+		t.Fatalf("un comando inválido no debe ejecutarse; find devolvió %v documentos", found["count"])
+	}
+}
+
+func TestAnalyzeDryRunDoesNotModify(t *testing.T) {
+	analyzer, ctx := newAnalyzer(t)
+	execute := entities.AnalysisOptions{}
+
+	run(t, analyzer, ctx, `use escuela`, execute)
+	run(t, analyzer, ctx, `db.alumnos.insertOne({ "_id": 1, "nombre": "Ana" })`, execute)
+
+	simulated := run(t, analyzer, ctx, `db.alumnos.deleteOne({ "nombre": "Ana" })`, entities.AnalysisOptions{Mode: entities.MODE_DRY_RUN})
+	if simulated["dryRun"] != true || simulated["matchedCount"] != int64(1) {
+		t.Fatalf("dry-run = %v", simulated)
+	}
+
+	found := run(t, analyzer, ctx, `db.alumnos.find({})`, execute)
+	if found["count"] != 1 {
+		t.Fatalf("el dry-run modificó la colección: quedan %v documentos", found["count"])
+	}
+}
+
+func TestAnalyzeRequiresConfirmationToDrop(t *testing.T) {
+	analyzer, ctx := newAnalyzer(t)
+	execute := entities.AnalysisOptions{}
+
+	run(t, analyzer, ctx, `use escuela`, execute)
+	run(t, analyzer, ctx, `db.alumnos.insertOne({ "nombre": "Ana" })`, execute)
+
+	first, err := analyzer.Analyze(ctx, `db.alumnos.drop()`, execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Policy == nil || first.Policy.Allowed || first.Policy.ConfirmationToken == "" {
+		t.Fatalf("drop sin confirmar debería pedir confirmación: %+v", first.Policy)
+	}
+	if found := run(t, analyzer, ctx, `db.alumnos.find({})`, execute); found["count"] != 1 {
+		t.Fatalf("drop se ejecutó sin confirmación")
+	}
+
+	run(t, analyzer, ctx, `db.alumnos.drop()`, entities.AnalysisOptions{Confirmation: first.Policy.ConfirmationToken})
+	if found := run(t, analyzer, ctx, `db.alumnos.find({})`, execute); found["count"] != 0 {
+		t.Fatalf("drop confirmado no eliminó la colección: quedan %v documentos", found["count"])
+	}
+}
+
+func TestAnalyzeSuggestsMisspelledFieldsInOrder(t *testing.T) {
+	analyzer, ctx := newAnalyzer(t)
+	entities.SessionFromContext(ctx).SetDatabase("escuela")
+	run(t, analyzer, ctx, `db.alumnos.insertOne({ "_id": 1, "nombre": "Ana", "edad": 20 })`, entities.AnalysisOptions{})
+
+	for i := 0; i < 10; i++ {
+		result, err := analyzer.Analyze(ctx, `db.alumnos.find({ "nombr": "Ana", "edda": 20 })`, entities.AnalysisOptions{Mode: entities.MODE_VALIDATE})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Suggestions) != 2 || !strings.Contains(result.Suggestions[0], "edda") || !strings.Contains(result.Suggestions[1], "nombr") {
+			t.Fatalf("sugerencias = %v", result.Suggestions)
+		}
+	}
+
+	result, err := analyzer.Analyze(ctx, `db.alumnos.find({ "nombre": "Ana" })`, entities.AnalysisOptions{Mode: entities.MODE_VALIDATE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Suggestions) != 0 {
+		t.Fatalf("un comando sin errores no debe tener sugerencias: %v", result.Suggestions)
+	}
+}
+
+// Validar consulta el catálogo y muestras de documentos: un rol que no puede
+// leer el espacio de nombres no obtiene ni el resultado ni sugerencias.
+func TestAnalyzeValidateRequiresReadAccess(t *testing.T) {
+	auth := policy.NewRoleAuthorizer(map[string]policy.Role{
+		"admin":  policy.DefaultRoles["admin"],
+		"alumno": {Commands: []entities.CommandType{entities.FIND}, Namespaces: []string{"escuela.alumnos"}},
+	})
+	analyzer, ctx := newAnalyzerWithAuth(t, auth)
+	entities.SessionFromContext(ctx).SetDatabase("escuela")
+
+	admin := entities.ContextWithPrincipal(ctx, &entities.Principal{Name: "admin", Role: "admin"})
+	run(t, analyzer, admin, `db.notas.insertOne({ "_id": 1, "nota": 7 })`, entities.AnalysisOptions{})
+	run(t, analyzer, admin, `db.alumnos.insertOne({ "_id": 1, "nombre": "Ana" })`, entities.AnalysisOptions{})
+
+	reader := entities.ContextWithPrincipal(ctx, &entities.Principal{Name: "ana", Role: "alumno"})
+	validate := entities.AnalysisOptions{Mode: entities.MODE_VALIDATE}
+
+	cases := []struct {
+		input   string
+		allowed bool
+	}{
+		{`db.alumnos.find({ "nombre": "Ana" })`, true},
+		{`db.alumnos.insertOne({ "nombre": "Luis" })`, true},
+		{`db.notas.find({ "nota": 7 })`, false},
+		{`db.nota.find({})`, false},
+		{`db.alumnos.find({ "nombr": "Ana" })`, true},
+	}
+	for _, tc := range cases {
+		result, err := analyzer.Analyze(reader, tc.input, validate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (result.Authorization == nil) != tc.allowed {
+			t.Errorf("%s: autorización %v, se esperaba permitido = %v", tc.input, result.Authorization, tc.allowed)
+		}
+		for _, suggestion := range result.Suggestions {
+			if strings.Contains(suggestion, "notas") {
+				t.Errorf("%s: la sugerencia %q revela una colección que el rol no puede leer", tc.input, suggestion)
+			}
+		}
+	}
+}
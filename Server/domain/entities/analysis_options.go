@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+type ExecutionMode string
+
+const (
+	// MODE_VALIDATE solo ejecuta el análisis léxico, sintáctico y semántico.
+	MODE_VALIDATE ExecutionMode = "validate"
+	// MODE_DRY_RUN informa qué afectaría el comando sin modificar nada.
+	MODE_DRY_RUN ExecutionMode = "dry-run"
+	// MODE_EXECUTE ejecuta el comando contra la base de datos.
+	MODE_EXECUTE ExecutionMode = "execute"
+)
+
+// ParseExecutionMode interpreta el modo pedido por el cliente. Un valor vacío
+// equivale a MODE_EXECUTE para no romper a los clientes existentes.
+func ParseExecutionMode(value string) (ExecutionMode, bool) {
+	switch ExecutionMode(value) {
+	case "", MODE_EXECUTE:
+		return MODE_EXECUTE, true
+	case MODE_VALIDATE, MODE_DRY_RUN:
+		return ExecutionMode(value), true
+	}
+	return "", false
+}
+
+// AnalysisOptions agrupa las preferencias del cliente para un análisis.
+type AnalysisOptions struct {
+	Locale string
+	Mode   ExecutionMode
+	// Timeout limita la duración del análisis y la ejecución. Cero significa
+	// que solo aplica el límite configurado en el servidor.
+	Timeout time.Duration
+	// Trace pide incluir tokens, AST, comprobaciones y tiempos por fase.
+	Trace bool
+	// Derivation pide el registro paso a paso del parser.
+	Derivation bool
+	// Lint cambia el nivel de las reglas de lint solo para esta petición.
+	Lint LintConfig
+	// Confirmation es el token devuelto por una petición anterior para
+	// confirmar un comando destructivo.
+	Confirmation string
+}
@@ -3,9 +3,16 @@ package entities
 type AnalysisResult struct {
 	Command          *MongoCommand
 	IsValid          bool
+	Mode             ExecutionMode
 	Errors           []string
 	TokenCount       int
 	SuggestedFix     string
+	Suggestions      []string
 	ExecutionResult  interface{}
 	ExecutionError   error
+	Trace            *AnalysisTrace
+	Derivation       *DerivationLog
+	Lint             []LintResult
+	Policy           *PolicyDecision
+	Authorization    *AuthorizationError
 }
\ No newline at end of file
@@ -0,0 +1,14 @@
+package entities
+
+// ASTNode es un nodo del árbol de derivación que construye el parser. Los
+// nodos internos llevan el nombre de la producción (Kind) y los nodos hoja
+// el token consumido.
+type ASTNode struct {
+	Kind     string
+	Token    *Token
+	Children []*ASTNode
+}
+
+func (n *ASTNode) IsLeaf() bool {
+	return n.Token != nil
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "context"
+
+// Principal es quien hace la petición, identificado por su API key.
+type Principal struct {
+	Name string
+	Role string
+}
+
+// AuthorizationError indica que el rol del principal no permite el
+// comando. Se informa aparte de los errores de ejecución.
+type AuthorizationError struct {
+	Role      string
+	Command   CommandType
+	Namespace string
+	Reason    *Diagnostic
+}
+
+func (e *AuthorizationError) Error() string {
+	return e.Reason.Error()
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal asocia el principal autenticado a la petición.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext devuelve el principal de la petición, o nil.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -11,8 +11,48 @@ const (
 	DELETE_ONE
 	DROP_COLLECTION
 	DROP_DATABASE
+	REPLACE_ONE
 )
 
+var commandTypeNames = map[CommandType]string{
+	USE_DATABASE:      "USE_DATABASE",
+	CREATE_COLLECTION: "CREATE_COLLECTION",
+	INSERT_ONE:        "INSERT_ONE",
+	FIND:              "FIND",
+	UPDATE_ONE:        "UPDATE_ONE",
+	DELETE_ONE:        "DELETE_ONE",
+	DROP_COLLECTION:   "DROP_COLLECTION",
+	DROP_DATABASE:     "DROP_DATABASE",
+	REPLACE_ONE:       "REPLACE_ONE",
+}
+
+func (t CommandType) String() string {
+	if name, ok := commandTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// ParseCommandType devuelve el tipo de comando con el nombre indicado
+// (por ejemplo "FIND").
+func ParseCommandType(name string) (CommandType, bool) {
+	for commandType, candidate := range commandTypeNames {
+		if candidate == name {
+			return commandType, true
+		}
+	}
+	return 0, false
+}
+
+// AllCommandTypes devuelve todos los tipos de comando.
+func AllCommandTypes() []CommandType {
+	types := make([]CommandType, 0, len(commandTypeNames))
+	for commandType := CommandType(0); int(commandType) < len(commandTypeNames); commandType++ {
+		types = append(types, commandType)
+	}
+	return types
+}
+
 type MongoCommand struct {
 	Type       CommandType
 	Database   string
@@ -20,7 +60,14 @@ type MongoCommand struct {
 	Document   map[string]interface{}
 	Filter     map[string]interface{}
 	Update     map[string]interface{}
-	IsValid    bool
-	Errors     []string
-	TokenCount int
-}
\ No newline at end of file
+	// UpdatePipeline contiene las etapas de una actualización estilo
+	// pipeline; en ese caso Update es nil.
+	UpdatePipeline []interface{}
+	IsValid        bool
+	Errors         []*Diagnostic
+	TokenCount     int
+	AST            *ASTNode
+	// Source es la entrada original, de la que se leen las directivas
+	// analyzer-disable-next-line.
+	Source string
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// CursorPage es un lote de documentos de un find. Si HasMore es cierto,
+// los siguientes se piden con ID antes de ExpiresAt.
+type CursorPage struct {
+	ID         string
+	Database   string
+	Collection string
+	Documents  []map[string]interface{}
+	// Returned es el número de documentos devueltos por el cursor hasta
+	// ahora, este lote incluido.
+	Returned int
+	HasMore  bool
+	// Truncated indica que el cursor se cerró al alcanzar el máximo de
+	// documentos por consulta aunque quedaban más.
+	Truncated bool
+	ExpiresAt time.Time
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "context"
+
+type DerivationAction string
+
+const (
+	DERIVATION_ENTER   DerivationAction = "enter"
+	DERIVATION_LEAVE   DerivationAction = "leave"
+	DERIVATION_CONSUME DerivationAction = "consume"
+	DERIVATION_BRANCH  DerivationAction = "branch"
+)
+
+// DerivationStep es un paso del parser descendente recursivo: entrar o salir
+// de una producción, consumir un token o elegir una alternativa.
+type DerivationStep struct {
+	Action     DerivationAction
+	Production string
+	Function   string
+	Depth      int
+	Token      *Token
+	Branch     string
+}
+
+// DerivationLog acumula los pasos en orden. Como AnalysisTrace, acepta un
+// receptor nil para que el parser registre sin comprobar si está activo.
+type DerivationLog struct {
+	Steps []DerivationStep
+}
+
+func (l *DerivationLog) Record(step DerivationStep) {
+	if l == nil {
+		return
+	}
+	l.Steps = append(l.Steps, step)
+}
+
+type derivationKey struct{}
+
+func ContextWithDerivation(ctx context.Context, log *DerivationLog) context.Context {
+	return context.WithValue(ctx, derivationKey{}, log)
+}
+
+// DerivationFromContext devuelve el registro asociado a ctx o nil si no hay.
+func DerivationFromContext(ctx context.Context) *DerivationLog {
+	log, _ := ctx.Value(derivationKey{}).(*DerivationLog)
+	return log
+}
@@ -0,0 +1,34 @@
+package entities
+
+import "mongo-analyzer/domain/i18n"
+
+// Diagnostic es un mensaje para el usuario identificado por un código del
+// catálogo de i18n. Se traduce al idioma del cliente al construir la
+// respuesta; Error() usa el idioma por defecto.
+type Diagnostic struct {
+	Code string
+	Args []interface{}
+}
+
+func NewDiagnostic(code string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{Code: code, Args: args}
+}
+
+func (d *Diagnostic) Error() string {
+	return d.Localize(i18n.DefaultLocale)
+}
+
+func (d *Diagnostic) Localize(locale string) string {
+	return i18n.Translate(locale, d.Code, d.Args...)
+}
+
+// Root devuelve el diagnóstico más interno cuando d envuelve a otro, por
+// ejemplo "Error en filtro: se esperaba ':'".
+func (d *Diagnostic) Root() *Diagnostic {
+	for _, arg := range d.Args {
+		if inner, ok := arg.(*Diagnostic); ok {
+			return inner.Root()
+		}
+	}
+	return d
+}
@@ -0,0 +1,54 @@
+package entities
+
+// ImportOptions indica dónde y cómo se importa un archivo.
+type ImportOptions struct {
+	Database   string
+	Collection string
+	// UpsertBy es el campo que identifica cada documento: si ya hay uno con
+	// el mismo valor, se reemplaza. Vacío inserta siempre.
+	UpsertBy  string
+	BatchSize int
+	Locale    string
+}
+
+// ImportRow es un documento leído del archivo o el error que impidió
+// leerlo.
+type ImportRow struct {
+	// Row es la línea en CSV y NDJSON, y la posición (desde 1) en un array
+	// JSON.
+	Row      int
+	Document map[string]interface{}
+	Err      error
+}
+
+// ImportRowError es una fila que no se importó y el motivo, ya traducido.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportProgress cuenta las filas procesadas hasta el momento. Errors son
+// solo las filas fallidas del último lote.
+type ImportProgress struct {
+	Rows     int              `json:"rows"`
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors,omitempty"`
+}
+
+// BatchWrite es un documento de un lote. Sin Filter se inserta; con Filter
+// reemplaza al primer documento que coincide o, si no hay ninguno, se
+// inserta.
+type BatchWrite struct {
+	Filter   map[string]interface{}
+	Document map[string]interface{}
+}
+
+// WriteOutcome es el resultado de un documento de un lote.
+type WriteOutcome struct {
+	// Updated indica que reemplazó a un documento existente; si no hay
+	// error y es false, se insertó.
+	Updated bool
+	Err     error
+}
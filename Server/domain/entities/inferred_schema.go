@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// InferredSchema describe los campos observados en una muestra de
+// documentos de una colección.
+type InferredSchema struct {
+	Database   string
+	Collection string
+	SampleSize int
+	SampledAt  time.Time
+	// Fields está ordenado por ruta. Los campos de subdocumentos usan la
+	// notación con puntos (address.city), también dentro de arrays.
+	Fields []FieldSchema
+}
+
+// FieldSchema resume un campo de la muestra.
+type FieldSchema struct {
+	Path string
+	// Types cuenta los tipos BSON observados (string, int, array...).
+	Types map[string]int
+	// ElementTypes cuenta los tipos de los elementos cuando el campo es un
+	// array.
+	ElementTypes map[string]int
+	// Count es el número de documentos que contienen el campo.
+	Count     int
+	Frequency float64
+	IsArray   bool
+}
+
+// Field busca un campo por su ruta.
+func (s *InferredSchema) Field(path string) (*FieldSchema, bool) {
+	for i := range s.Fields {
+		if s.Fields[i].Path == path {
+			return &s.Fields[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,57 @@
+package entities
+
+// Severity es el nivel con el que se informa una regla de lint. Las reglas
+// con nivel SEVERITY_ERROR invalidan el comando; SEVERITY_OFF las desactiva.
+type Severity string
+
+const (
+	SEVERITY_OFF     Severity = "off"
+	SEVERITY_INFO    Severity = "info"
+	SEVERITY_WARNING Severity = "warning"
+	SEVERITY_ERROR   Severity = "error"
+)
+
+// ParseSeverity interpreta el nivel recibido en la configuración o la
+// petición.
+func ParseSeverity(value string) (Severity, bool) {
+	switch severity := Severity(value); severity {
+	case SEVERITY_OFF, SEVERITY_INFO, SEVERITY_WARNING, SEVERITY_ERROR:
+		return severity, true
+	}
+	return "", false
+}
+
+// LintConfig asigna un nivel a cada regla por su ID. Las reglas ausentes
+// usan su nivel por defecto.
+type LintConfig map[string]Severity
+
+// Merge devuelve una copia de c con los niveles de overrides aplicados
+// encima.
+func (c LintConfig) Merge(overrides LintConfig) LintConfig {
+	merged := make(LintConfig, len(c)+len(overrides))
+	for rule, severity := range c {
+		merged[rule] = severity
+	}
+	for rule, severity := range overrides {
+		merged[rule] = severity
+	}
+	return merged
+}
+
+// LintFinding es un patrón de riesgo detectado por una regla. Line es la
+// línea de la entrada a la que se refiere, usada por las directivas
+// analyzer-disable-next-line.
+type LintFinding struct {
+	Rule       string
+	Severity   Severity
+	Line       int
+	Diagnostic *Diagnostic
+}
+
+// LintResult es un hallazgo ya traducido al idioma de la petición.
+type LintResult struct {
+	Rule     string
+	Severity Severity
+	Line     int
+	Message  string
+}
@@ -0,0 +1,22 @@
+package entities
+
+// QueryOperators son los operadores válidos dentro de un filtro.
+var QueryOperators = []string{
+	"$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in", "$nin",
+	"$exists", "$type", "$regex", "$options", "$elemMatch", "$size", "$all",
+	"$and", "$or", "$nor", "$not", "$expr", "$mod", "$where", "$text", "$jsonSchema", "$comment",
+}
+
+// UpdateOperators son los operadores válidos en el documento de actualización.
+var UpdateOperators = []string{
+	"$set", "$unset", "$inc", "$mul", "$rename", "$min", "$max", "$currentDate",
+	"$setOnInsert", "$addToSet", "$pop", "$pull", "$pullAll", "$push",
+	"$each", "$slice", "$sort", "$position",
+}
+
+// AggregationOperators son las etapas y expresiones de agregación más comunes.
+var AggregationOperators = []string{
+	"$match", "$project", "$group", "$sort", "$limit", "$skip", "$unwind", "$lookup",
+	"$addFields", "$count", "$replaceRoot", "$replaceWith", "$facet", "$out", "$merge",
+	"$sum", "$avg", "$first", "$last", "$push", "$concat", "$cond", "$ifNull", "$function",
+}
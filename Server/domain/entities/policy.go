@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// CommandClass clasifica los comandos según su efecto sobre los datos.
+type CommandClass string
+
+const (
+	CLASS_READ        CommandClass = "read"
+	CLASS_WRITE       CommandClass = "write"
+	CLASS_DESTRUCTIVE CommandClass = "destructive"
+)
+
+// ClassOf devuelve la clase de un tipo de comando. Los destructivos
+// eliminan colecciones o bases de datos completas.
+func ClassOf(commandType CommandType) CommandClass {
+	switch commandType {
+	case USE_DATABASE, FIND:
+		return CLASS_READ
+	case DROP_COLLECTION, DROP_DATABASE:
+		return CLASS_DESTRUCTIVE
+	}
+	return CLASS_WRITE
+}
+
+// PolicyDecision es el resultado de pasar un comando por la política de
+// ejecución. Si Allowed es falso el comando no se ejecuta: o bien está
+// prohibido (Reason) o necesita confirmación (ConfirmationToken).
+type PolicyDecision struct {
+	Class   CommandClass
+	Allowed bool
+	Reason  *Diagnostic
+	// ConfirmationToken debe reenviarse en una segunda petición, antes de
+	// ExpiresAt, para ejecutar el comando.
+	ConfirmationToken string
+	ExpiresAt         time.Time
+}
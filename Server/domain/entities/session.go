@@ -0,0 +1,142 @@
+package entities
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxSessionHistory es el número de comandos que guarda el historial de una
+// sesión; los más antiguos se descartan.
+const maxSessionHistory = 100
+
+// HistoryEntry es un comando analizado en la sesión.
+type HistoryEntry struct {
+	Command string
+	Mode    ExecutionMode
+	IsValid bool
+	At      time.Time
+}
+
+// SessionSettings son las preferencias de la sesión que se aplican cuando
+// la petición no indica otras.
+type SessionSettings struct {
+	Locale string
+	Mode   ExecutionMode
+	Lint   LintConfig
+}
+
+// Session es el estado de un cliente: su base de datos actual, su
+// historial y sus preferencias. Es segura para uso concurrente y sus
+// métodos admiten un receptor nil, que no guarda nada.
+type Session struct {
+	ID string
+
+	mu       sync.Mutex
+	database string
+	history  []HistoryEntry
+	settings SessionSettings
+	lastSeen time.Time
+}
+
+func NewSession(id string, now time.Time) *Session {
+	return &Session{ID: id, lastSeen: now}
+}
+
+func (s *Session) Database() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.database
+}
+
+func (s *Session) SetDatabase(database string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.database = database
+	s.mu.Unlock()
+}
+
+// ClearDatabase olvida la base de datos actual si es database, por ejemplo
+// porque se ha eliminado.
+func (s *Session) ClearDatabase(database string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.database == database {
+		s.database = ""
+	}
+	s.mu.Unlock()
+}
+
+func (s *Session) Record(entry HistoryEntry) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, entry)
+	if len(s.history) > maxSessionHistory {
+		s.history = s.history[len(s.history)-maxSessionHistory:]
+	}
+}
+
+// History devuelve una copia del historial, del más antiguo al más reciente.
+func (s *Session) History() []HistoryEntry {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]HistoryEntry(nil), s.history...)
+}
+
+func (s *Session) Settings() SessionSettings {
+	if s == nil {
+		return SessionSettings{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.settings
+}
+
+func (s *Session) SetSettings(settings SessionSettings) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.settings = settings
+	s.mu.Unlock()
+}
+
+// Touch marca la sesión como usada en now.
+func (s *Session) Touch(now time.Time) {
+	s.mu.Lock()
+	s.lastSeen = now
+	s.mu.Unlock()
+}
+
+// IdleSince indica si la sesión no se usa desde antes de limit.
+func (s *Session) IdleSince(limit time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeen.Before(limit)
+}
+
+type sessionKey struct{}
+
+// ContextWithSession asocia la sesión del cliente a la petición.
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext devuelve la sesión de la petición, o nil.
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
@@ -17,10 +17,44 @@ const (
 	COMMA
 	COLON
 	DOLLAR_SIGN
+	LEFT_BRACKET
+	RIGHT_BRACKET
+	BOOLEAN
+	NULL
 	EOF
 	INVALID
 )
 
+var tokenTypeNames = map[TokenType]string{
+	USE:           "USE",
+	DB:            "DB",
+	DOT:           "DOT",
+	IDENTIFIER:    "IDENTIFIER",
+	FUNCTION:      "FUNCTION",
+	LEFT_PAREN:    "LEFT_PAREN",
+	RIGHT_PAREN:   "RIGHT_PAREN",
+	LEFT_BRACE:    "LEFT_BRACE",
+	RIGHT_BRACE:   "RIGHT_BRACE",
+	STRING:        "STRING",
+	NUMBER:        "NUMBER",
+	COMMA:         "COMMA",
+	COLON:         "COLON",
+	DOLLAR_SIGN:   "DOLLAR_SIGN",
+	LEFT_BRACKET:  "LEFT_BRACKET",
+	RIGHT_BRACKET: "RIGHT_BRACKET",
+	BOOLEAN:       "BOOLEAN",
+	NULL:          "NULL",
+	EOF:           "EOF",
+	INVALID:       "INVALID",
+}
+
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
 type Token struct {
 	Type     TokenType
 	Value    string
@@ -28,3 +62,6 @@ type Token struct {
 	Line     int
 	Column   int
 }
+
+// KnownFunctions lista las funciones que el lexer reconoce como FUNCTION.
+var KnownFunctions = []string{"createCollection", "insertOne", "find", "updateOne", "replaceOne", "deleteOne", "drop", "dropDatabase"}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// TraceCheck registra una comprobación del validador y su resultado.
+type TraceCheck struct {
+	Name  string
+	Error error
+}
+
+// PhaseTiming es el tiempo de reloj que tomó una fase del análisis.
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// AnalysisTrace recoge el detalle interno de un análisis cuando el cliente
+// lo pide. Todos los métodos aceptan un receptor nil para que los
+// componentes puedan registrar sin comprobar si el trazado está activo.
+type AnalysisTrace struct {
+	Tokens  []*Token
+	AST     *ASTNode
+	Checks  []TraceCheck
+	Timings []PhaseTiming
+}
+
+func (t *AnalysisTrace) RecordCheck(name string, err error) {
+	if t == nil {
+		return
+	}
+	t.Checks = append(t.Checks, TraceCheck{Name: name, Error: err})
+}
+
+func (t *AnalysisTrace) RecordPhase(phase string, started time.Time) {
+	if t == nil {
+		return
+	}
+	t.Timings = append(t.Timings, PhaseTiming{Phase: phase, Duration: time.Since(started)})
+}
+
+type traceKey struct{}
+
+func ContextWithTrace(ctx context.Context, trace *AnalysisTrace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// TraceFromContext devuelve el trazado asociado a ctx o nil si no hay.
+func TraceFromContext(ctx context.Context) *AnalysisTrace {
+	trace, _ := ctx.Value(traceKey{}).(*AnalysisTrace)
+	return trace
+}
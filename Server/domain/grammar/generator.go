@@ -0,0 +1,187 @@
+package grammar
+
+import (
+	"math/rand"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// Palabras que el lexer convierte en tokens propios y que por lo tanto no
+// pueden generarse como IDENTIFIER.
+var reservedWords = map[string]bool{"use": true, "db": true, "true": true, "false": true, "null": true}
+
+// generatedTokens son las producciones léxicas para las que el generador
+// produce el lexema directamente.
+var generatedTokens = map[string]bool{"IDENTIFIER": true, "STRING": true, "NUMBER": true}
+
+// Generator produce oraciones aleatorias de la gramática. Las producciones
+// léxicas se generan directamente como lexemas válidos.
+type Generator struct {
+	grammar  *Grammar
+	rand     *rand.Rand
+	maxDepth int
+	minDepth map[string]int
+}
+
+func NewGenerator(grammar *Grammar, seed int64, maxDepth int) *Generator {
+	g := &Generator{
+		grammar:  grammar,
+		rand:     rand.New(rand.NewSource(seed)),
+		maxDepth: maxDepth,
+	}
+	g.computeMinDepth()
+	return g
+}
+
+// Sentence genera una oración a partir del símbolo inicial, con los tokens
+// separados por espacios o saltos de línea.
+func (g *Generator) Sentence() string {
+	var lexemes []string
+	g.expand(nt(g.grammar.Start), 0, &lexemes)
+
+	var b strings.Builder
+	for i, lexeme := range lexemes {
+		if i > 0 {
+			b.WriteString([]string{" ", " ", "  ", "\n"}[g.rand.Intn(4)])
+		}
+		b.WriteString(lexeme)
+	}
+	return b.String()
+}
+
+func (g *Generator) expand(expr Expr, depth int, out *[]string) {
+	switch e := expr.(type) {
+	case Terminal:
+		*out = append(*out, e.Text)
+	case NonTerminal:
+		if lexeme, ok := g.lexeme(e.Name); ok {
+			*out = append(*out, lexeme)
+			return
+		}
+		production, _ := g.grammar.Production(e.Name)
+		g.expand(production.Expr, depth+1, out)
+	case Sequence:
+		for _, item := range e.Items {
+			g.expand(item, depth, out)
+		}
+	case Choice:
+		g.expand(g.choose(e, depth), depth, out)
+	case Optional:
+		if depth < g.maxDepth && g.rand.Intn(2) == 0 {
+			g.expand(e.Item, depth, out)
+		}
+	case Repetition:
+		if depth < g.maxDepth {
+			for n := g.rand.Intn(3); n > 0; n-- {
+				g.expand(e.Item, depth, out)
+			}
+		}
+	}
+}
+
+// choose elige una alternativa al azar o, pasado maxDepth, la que termina
+// antes para asegurar que la generación acaba.
+func (g *Generator) choose(choice Choice, depth int) Expr {
+	if depth < g.maxDepth {
+		return choice.Alternatives[g.rand.Intn(len(choice.Alternatives))]
+	}
+
+	best := choice.Alternatives[0]
+	for _, alternative := range choice.Alternatives[1:] {
+		if g.depthOf(alternative) < g.depthOf(best) {
+			best = alternative
+		}
+	}
+	return best
+}
+
+func (g *Generator) lexeme(name string) (string, bool) {
+	switch name {
+	case "IDENTIFIER":
+		for {
+			identifier := g.word("abcdefghijklmnopqrstuvwxyz_", "abcdefghijklmnopqrstuvwxyz_0123456789", 8)
+			if !reservedWords[identifier] && !isFunction(identifier) {
+				return identifier, true
+			}
+		}
+	case "STRING":
+		return `"` + g.word("abcdefghijklmnopqrstuvwxyz ", "abcdefghijklmnopqrstuvwxyz 0123456789.$_-", 12) + `"`, true
+	case "NUMBER":
+		number := g.word("0123456789", "0123456789", 5)
+		if g.rand.Intn(4) == 0 {
+			number = "-" + number
+		}
+		if g.rand.Intn(3) == 0 {
+			number += "." + g.word("0123456789", "0123456789", 3)
+		}
+		return number, true
+	}
+	return "", false
+}
+
+func (g *Generator) word(first, rest string, maxLength int) string {
+	length := 1 + g.rand.Intn(maxLength)
+	b := []byte{first[g.rand.Intn(len(first))]}
+	for len(b) < length {
+		b = append(b, rest[g.rand.Intn(len(rest))])
+	}
+	return string(b)
+}
+
+// computeMinDepth calcula por punto fijo la profundidad mínima de
+// derivación de cada producción.
+func (g *Generator) computeMinDepth() {
+	const infinite = 1 << 20
+	g.minDepth = make(map[string]int)
+	for _, production := range g.grammar.Productions {
+		g.minDepth[production.Name] = infinite
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, production := range g.grammar.Productions {
+			depth := 1 + g.depthOf(production.Expr)
+			if depth < g.minDepth[production.Name] {
+				g.minDepth[production.Name] = depth
+				changed = true
+			}
+		}
+	}
+}
+
+func (g *Generator) depthOf(expr Expr) int {
+	switch e := expr.(type) {
+	case NonTerminal:
+		if generatedTokens[e.Name] {
+			return 0
+		}
+		return g.minDepth[e.Name]
+	case Sequence:
+		deepest := 0
+		for _, item := range e.Items {
+			if d := g.depthOf(item); d > deepest {
+				deepest = d
+			}
+		}
+		return deepest
+	case Choice:
+		shallowest := g.depthOf(e.Alternatives[0])
+		for _, alternative := range e.Alternatives[1:] {
+			if d := g.depthOf(alternative); d < shallowest {
+				shallowest = d
+			}
+		}
+		return shallowest
+	}
+	return 0
+}
+
+func isFunction(word string) bool {
+	for _, fn := range entities.KnownFunctions {
+		if fn == word {
+			return true
+		}
+	}
+	return false
+}
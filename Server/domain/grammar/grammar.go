@@ -0,0 +1,160 @@
+// Package grammar describe formalmente el lenguaje que acepta MongoParser.
+// La misma especificación se publica como EBNF, se dibuja como diagramas
+// de sintaxis y se usa para generar oraciones en la prueba de conformidad,
+// de modo que el parser y la documentación no puedan divergir.
+package grammar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr es una expresión EBNF.
+type Expr interface {
+	ebnf() string
+}
+
+// Terminal es un literal que aparece tal cual en la entrada.
+type Terminal struct{ Text string }
+
+// NonTerminal hace referencia a otra producción.
+type NonTerminal struct{ Name string }
+
+// Special es una secuencia especial de EBNF (? ... ?) que describe en prosa
+// un conjunto de caracteres.
+type Special struct{ Description string }
+
+type Sequence struct{ Items []Expr }
+
+type Choice struct{ Alternatives []Expr }
+
+// Optional es [ Item ].
+type Optional struct{ Item Expr }
+
+// Repetition es { Item }: cero o más repeticiones.
+type Repetition struct{ Item Expr }
+
+func (t Terminal) ebnf() string {
+	if strings.Contains(t.Text, `"`) {
+		return "'" + t.Text + "'"
+	}
+	return `"` + t.Text + `"`
+}
+
+func (n NonTerminal) ebnf() string { return n.Name }
+
+func (s Special) ebnf() string { return "? " + s.Description + " ?" }
+
+func (s Sequence) ebnf() string {
+	parts := make([]string, len(s.Items))
+	for i, item := range s.Items {
+		parts[i] = item.ebnf()
+		if _, ok := item.(Choice); ok {
+			parts[i] = "( " + parts[i] + " )"
+		}
+	}
+	return strings.Join(parts, " , ")
+}
+
+func (c Choice) ebnf() string {
+	parts := make([]string, len(c.Alternatives))
+	for i, alternative := range c.Alternatives {
+		parts[i] = alternative.ebnf()
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (o Optional) ebnf() string { return "[ " + o.Item.ebnf() + " ]" }
+
+func (r Repetition) ebnf() string { return "{ " + r.Item.ebnf() + " }" }
+
+// Production es una regla nombre = expresión. Las producciones léxicas
+// describen tokens; el resto coincide con los nodos del árbol del parser.
+type Production struct {
+	Name    string
+	Expr    Expr
+	Lexical bool
+}
+
+type Grammar struct {
+	Start       string
+	Productions []Production
+}
+
+// Production devuelve la producción con ese nombre.
+func (g *Grammar) Production(name string) (Production, bool) {
+	for _, production := range g.Productions {
+		if production.Name == name {
+			return production, true
+		}
+	}
+	return Production{}, false
+}
+
+// EBNF devuelve la gramática en notación ISO/IEC 14977.
+func (g *Grammar) EBNF() string {
+	width := 0
+	for _, production := range g.Productions {
+		if len(production.Name) > width {
+			width = len(production.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("(* Gramática de comandos aceptada por mongo-analyzer *)\n\n")
+
+	lexical := false
+	for _, production := range g.Productions {
+		if production.Lexical && !lexical {
+			b.WriteString("\n(* Tokens *)\n")
+			lexical = true
+		}
+		fmt.Fprintf(&b, "%-*s = %s ;\n", width, production.Name, production.Expr.ebnf())
+	}
+
+	return b.String()
+}
+
+func t(text string) Terminal     { return Terminal{Text: text} }
+func nt(name string) NonTerminal { return NonTerminal{Name: name} }
+func seq(items ...Expr) Sequence { return Sequence{Items: items} }
+func alt(items ...Expr) Choice   { return Choice{Alternatives: items} }
+func opt(item Expr) Optional     { return Optional{Item: item} }
+func rep(item Expr) Repetition   { return Repetition{Item: item} }
+
+// MongoGrammar devuelve la gramática que implementa MongoParser. Los nombres
+// de las producciones sintácticas son los mismos que usa el parser para los
+// nodos del árbol de derivación.
+func MongoGrammar() *Grammar {
+	return &Grammar{
+		Start: "command",
+		Productions: []Production{
+			{Name: "command", Expr: alt(nt("use_command"), nt("db_command"))},
+			{Name: "use_command", Expr: seq(t("use"), nt("IDENTIFIER"),
+				opt(seq(t("db"), t("."), t("dropDatabase"), opt(seq(t("("), t(")"))))))},
+			{Name: "db_command", Expr: seq(t("db"), t("."), alt(
+				nt("create_collection"),
+				nt("drop_database"),
+				seq(nt("IDENTIFIER"), t("."), alt(nt("insert_one"), nt("find"), nt("update_one"), nt("replace_one"), nt("delete_one"), nt("drop"))),
+			))},
+			{Name: "create_collection", Expr: seq(t("createCollection"), t("("), nt("STRING"), t(")"))},
+			{Name: "drop_database", Expr: seq(t("dropDatabase"), t("("), t(")"))},
+			{Name: "insert_one", Expr: seq(t("insertOne"), t("("), nt("document"), t(")"))},
+			{Name: "find", Expr: seq(t("find"), t("("), opt(nt("document")), t(")"))},
+			{Name: "update_one", Expr: seq(t("updateOne"), t("("), nt("document"), t(","), alt(nt("document"), nt("array")), t(")"))},
+			{Name: "replace_one", Expr: seq(t("replaceOne"), t("("), nt("document"), t(","), nt("document"), t(")"))},
+			{Name: "delete_one", Expr: seq(t("deleteOne"), t("("), nt("document"), t(")"))},
+			{Name: "drop", Expr: seq(t("drop"), t("("), t(")"))},
+			{Name: "document", Expr: seq(t("{"), opt(seq(nt("pair"), rep(seq(t(","), nt("pair"))))), t("}"))},
+			{Name: "pair", Expr: seq(alt(nt("STRING"), nt("IDENTIFIER"), seq(t("$"), nt("IDENTIFIER"))), t(":"), nt("value"))},
+			{Name: "value", Expr: alt(nt("STRING"), nt("NUMBER"), nt("document"), nt("array"), t("true"), t("false"), t("null"), nt("IDENTIFIER"), seq(t("$"), nt("IDENTIFIER")))},
+			{Name: "array", Expr: seq(t("["), opt(seq(nt("value"), rep(seq(t(","), nt("value"))))), t("]"))},
+
+			{Name: "IDENTIFIER", Lexical: true, Expr: seq(alt(nt("letter"), t("_")), rep(alt(nt("letter"), nt("digit"), t("_"))))},
+			{Name: "STRING", Lexical: true, Expr: seq(t(`"`), rep(Special{"cualquier carácter excepto comillas dobles"}), t(`"`))},
+			{Name: "NUMBER", Lexical: true, Expr: seq(opt(t("-")), nt("digit"), rep(nt("digit")), opt(seq(t("."), nt("digit"), rep(nt("digit")))))},
+			{Name: "letter", Lexical: true, Expr: Special{"letra Unicode"}},
+			{Name: "digit", Lexical: true, Expr: Special{"dígito decimal 0-9"}},
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale es el idioma que se usa cuando el cliente no pide otro o
+// pide uno que no está en el catálogo.
+const DefaultLocale = "es"
+
+// Localizable lo implementan los valores que saben traducirse a sí mismos,
+// como los diagnósticos del analizador.
+type Localizable interface {
+	Localize(locale string) string
+}
+
+var catalogs = map[string]map[string]string{
+	"es": messagesES,
+	"en": messagesEN,
+}
+
+// SupportedLocales devuelve los idiomas disponibles en el catálogo.
+func SupportedLocales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Translate formatea el mensaje asociado a code en el idioma indicado. Los
+// argumentos Localizable y los errores se traducen con el mismo idioma antes
+// de formatear. Si el código no existe en el idioma pedido se usa el idioma
+// por defecto, y si tampoco existe ahí se devuelve el propio código.
+func Translate(locale, code string, args ...interface{}) string {
+	format, ok := catalogs[normalize(locale)][code]
+	if !ok {
+		format, ok = catalogs[DefaultLocale][code]
+	}
+	if !ok {
+		return code
+	}
+
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case Localizable:
+			localized[i] = value.Localize(locale)
+		case error:
+			localized[i] = value.Error()
+		default:
+			localized[i] = arg
+		}
+	}
+
+	return fmt.Sprintf(format, localized...)
+}
+
+// LocalizeError traduce err si es Localizable o devuelve su texto tal cual.
+func LocalizeError(err error, locale string) string {
+	if err == nil {
+		return ""
+	}
+	if localizable, ok := err.(Localizable); ok {
+		return localizable.Localize(locale)
+	}
+	return err.Error()
+}
+
+// MatchLocale elige el idioma a usar a partir de una lista de preferencias en
+// orden de prioridad. Cada preferencia puede ser un código simple ("en") o un
+// valor completo de la cabecera Accept-Language ("en-US,en;q=0.9,es;q=0.8").
+func MatchLocale(preferences ...string) string {
+	for _, preference := range preferences {
+		for _, tag := range parseAcceptLanguage(preference) {
+			if _, ok := catalogs[normalize(tag)]; ok {
+				return normalize(tag)
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+type weightedTag struct {
+	tag    string
+	weight float64
+}
+
+func parseAcceptLanguage(header string) []string {
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		if weight > 0 {
+			tags = append(tags, weightedTag{tag: tag, weight: weight})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].weight > tags[j].weight })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// normalize reduce una etiqueta como "en-US" o "ES_mx" a su subetiqueta
+// principal en minúsculas.
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
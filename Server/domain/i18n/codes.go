@@ -0,0 +1,240 @@
+package i18n
+
+// Códigos de diagnóstico. Cada código tiene una entrada en todos los
+// catálogos de mensajes.
+const (
+	// Análisis léxico
+	LexInvalidToken = "LEX_INVALID_TOKEN"
+
+	// Análisis sintáctico
+	ParseUnknownCommand                 = "PARSE_UNKNOWN_COMMAND"
+	ParseExpectedDatabaseName           = "PARSE_EXPECTED_DATABASE_NAME"
+	ParseExpectedDotAfterDB             = "PARSE_EXPECTED_DOT_AFTER_DB"
+	ParseExpectedDotAfterCollection     = "PARSE_EXPECTED_DOT_AFTER_COLLECTION"
+	ParseExpectedFunction               = "PARSE_EXPECTED_FUNCTION"
+	ParseUnknownFunction                = "PARSE_UNKNOWN_FUNCTION"
+	ParseInvalidDBCommand               = "PARSE_INVALID_DB_COMMAND"
+	ParseExpectedLeftParen              = "PARSE_EXPECTED_LEFT_PAREN"
+	ParseExpectedRightParen             = "PARSE_EXPECTED_RIGHT_PAREN"
+	ParseExpectedRightParenCollection   = "PARSE_EXPECTED_RIGHT_PAREN_COLLECTION"
+	ParseExpectedRightParenDocument     = "PARSE_EXPECTED_RIGHT_PAREN_DOCUMENT"
+	ParseExpectedRightParenFilter       = "PARSE_EXPECTED_RIGHT_PAREN_FILTER"
+	ParseExpectedCollectionString       = "PARSE_EXPECTED_COLLECTION_STRING"
+	ParseExpectedCommaFilterUpdate      = "PARSE_EXPECTED_COMMA_FILTER_UPDATE"
+	ParseFilterError                    = "PARSE_FILTER_ERROR"
+	ParseUpdateError                    = "PARSE_UPDATE_ERROR"
+	ParseExpectedCommaFilterReplacement = "PARSE_EXPECTED_COMMA_FILTER_REPLACEMENT"
+	ParseReplacementError               = "PARSE_REPLACEMENT_ERROR"
+	ParseExpectedLeftBrace              = "PARSE_EXPECTED_LEFT_BRACE"
+	ParseExpectedIdentifierAfterDollar  = "PARSE_EXPECTED_IDENTIFIER_AFTER_DOLLAR"
+	ParseExpectedKey                    = "PARSE_EXPECTED_KEY"
+	ParseExpectedColon                  = "PARSE_EXPECTED_COLON"
+	ParseExpectedCommaOrRightBrace      = "PARSE_EXPECTED_COMMA_OR_RIGHT_BRACE"
+	ParseExpectedCommaOrRightBracket    = "PARSE_EXPECTED_COMMA_OR_RIGHT_BRACKET"
+	ParseInvalidValue                   = "PARSE_INVALID_VALUE"
+	ParseInvalidNumber                  = "PARSE_INVALID_NUMBER"
+	ParseTrailingTokens                 = "PARSE_TRAILING_TOKENS"
+
+	// Análisis semántico
+	SemInvalidSyntax             = "SEM_INVALID_SYNTAX"
+	SemDatabaseNameEmpty         = "SEM_DATABASE_NAME_EMPTY"
+	SemDatabaseNameChars         = "SEM_DATABASE_NAME_CHARS"
+	SemDatabaseNameTooLong       = "SEM_DATABASE_NAME_TOO_LONG"
+	SemDatabaseNameNull          = "SEM_DATABASE_NAME_NULL"
+	SemDatabaseNameCaseClash     = "SEM_DATABASE_NAME_CASE_CLASH"
+	SemCollectionNameEmpty       = "SEM_COLLECTION_NAME_EMPTY"
+	SemCollectionNameDollar      = "SEM_COLLECTION_NAME_DOLLAR"
+	SemCollectionNameNull        = "SEM_COLLECTION_NAME_NULL"
+	SemCollectionNameReserved    = "SEM_COLLECTION_NAME_RESERVED"
+	SemNamespaceTooLong          = "SEM_NAMESPACE_TOO_LONG"
+	SemInsertEmpty               = "SEM_INSERT_EMPTY"
+	SemDocumentKeyEmpty          = "SEM_DOCUMENT_KEY_EMPTY"
+	SemDocumentKeyDollar         = "SEM_DOCUMENT_KEY_DOLLAR"
+	SemFieldNameNull             = "SEM_FIELD_NAME_NULL"
+	SemFieldNameEmptySegment     = "SEM_FIELD_NAME_EMPTY_SEGMENT"
+	SemDocumentTooDeep           = "SEM_DOCUMENT_TOO_DEEP"
+	SemDocumentTooLarge          = "SEM_DOCUMENT_TOO_LARGE"
+	SemUpdateFilterEmpty         = "SEM_UPDATE_FILTER_EMPTY"
+	SemUpdateEmpty               = "SEM_UPDATE_EMPTY"
+	SemUpdateNoOperator          = "SEM_UPDATE_NO_OPERATOR"
+	SemReplaceFilterEmpty        = "SEM_REPLACE_FILTER_EMPTY"
+	SemReplaceOperator           = "SEM_REPLACE_OPERATOR"
+	SemDeleteFilterEmpty         = "SEM_DELETE_FILTER_EMPTY"
+	SemUnknownQueryOperator      = "SEM_UNKNOWN_QUERY_OPERATOR"
+	SemOperatorNeedsField        = "SEM_OPERATOR_NEEDS_FIELD"
+	SemLogicalOperatorInField    = "SEM_LOGICAL_OPERATOR_IN_FIELD"
+	SemMixedOperatorsAndFields   = "SEM_MIXED_OPERATORS_AND_FIELDS"
+	SemOptionsWithoutRegex       = "SEM_OPTIONS_WITHOUT_REGEX"
+	SemUnknownUpdateOperator     = "SEM_UNKNOWN_UPDATE_OPERATOR"
+	SemUpdateMixedFields         = "SEM_UPDATE_MIXED_FIELDS"
+	SemUpdateOperatorEmpty       = "SEM_UPDATE_OPERATOR_EMPTY"
+	SemUnknownUpdateModifier     = "SEM_UNKNOWN_UPDATE_MODIFIER"
+	SemUpdateModifierWithoutEach = "SEM_UPDATE_MODIFIER_WITHOUT_EACH"
+	SemUpdatePathConflict        = "SEM_UPDATE_PATH_CONFLICT"
+	SemUpdatePipelineEmpty       = "SEM_UPDATE_PIPELINE_EMPTY"
+	SemUpdatePipelineStageShape  = "SEM_UPDATE_PIPELINE_STAGE_SHAPE"
+	SemUpdatePipelineStage       = "SEM_UPDATE_PIPELINE_STAGE"
+	SemSchemaType                = "SEM_SCHEMA_TYPE"
+	SemSchemaRequired            = "SEM_SCHEMA_REQUIRED"
+	SemSchemaAdditional          = "SEM_SCHEMA_ADDITIONAL"
+	SemSchemaEnum                = "SEM_SCHEMA_ENUM"
+	SemSchemaMinimum             = "SEM_SCHEMA_MINIMUM"
+	SemSchemaMaximum             = "SEM_SCHEMA_MAXIMUM"
+	SemSchemaMultipleOf          = "SEM_SCHEMA_MULTIPLE_OF"
+	SemSchemaMinLength           = "SEM_SCHEMA_MIN_LENGTH"
+	SemSchemaMaxLength           = "SEM_SCHEMA_MAX_LENGTH"
+	SemSchemaPattern             = "SEM_SCHEMA_PATTERN"
+	SemSchemaMinItems            = "SEM_SCHEMA_MIN_ITEMS"
+	SemSchemaMaxItems            = "SEM_SCHEMA_MAX_ITEMS"
+	SemSchemaUniqueItems         = "SEM_SCHEMA_UNIQUE_ITEMS"
+	SemSchemaMinProperties       = "SEM_SCHEMA_MIN_PROPERTIES"
+	SemSchemaMaxProperties       = "SEM_SCHEMA_MAX_PROPERTIES"
+	SemSchemaAnyOf               = "SEM_SCHEMA_ANY_OF"
+	SemSchemaOneOf               = "SEM_SCHEMA_ONE_OF"
+	SemSchemaNot                 = "SEM_SCHEMA_NOT"
+	SemOperandType               = "SEM_OPERAND_TYPE"
+
+	// Tipos esperados para los operandos
+	TypeString             = "TYPE_STRING"
+	TypeDocument           = "TYPE_DOCUMENT"
+	TypeArray              = "TYPE_ARRAY"
+	TypeBoolean            = "TYPE_BOOLEAN"
+	TypeNonNegativeInteger = "TYPE_NON_NEGATIVE_INTEGER"
+	TypeBSONType           = "TYPE_BSON_TYPE"
+	TypeRegexOptions       = "TYPE_REGEX_OPTIONS"
+	TypeModOperand         = "TYPE_MOD_OPERAND"
+	TypeFilterArray        = "TYPE_FILTER_ARRAY"
+	TypeOperatorDocument   = "TYPE_OPERATOR_DOCUMENT"
+	TypeNumber             = "TYPE_NUMBER"
+	TypeInteger            = "TYPE_INTEGER"
+	TypeRenameTarget       = "TYPE_RENAME_TARGET"
+	TypeCurrentDate        = "TYPE_CURRENT_DATE"
+	TypePopDirection       = "TYPE_POP_DIRECTION"
+	TypeSortSpec           = "TYPE_SORT_SPEC"
+	TypeFieldList          = "TYPE_FIELD_LIST"
+	SchemaRootDocument     = "SCHEMA_ROOT_DOCUMENT"
+
+	// Resultado del análisis
+	AnalyzeLexicalError  = "ANALYZE_LEXICAL_ERROR"
+	AnalyzeSyntaxError   = "ANALYZE_SYNTAX_ERROR"
+	AnalyzeSemanticError = "ANALYZE_SEMANTIC_ERROR"
+	AnalyzeLintError     = "ANALYZE_LINT_ERROR"
+
+	// Reglas de lint
+	LintFindWithoutFilter      = "LINT_FIND_WITHOUT_FILTER"
+	LintServerSideJavaScript   = "LINT_SERVER_SIDE_JAVASCRIPT"
+	LintRegexUnanchored        = "LINT_REGEX_UNANCHORED"
+	LintRegexCaseInsensitive   = "LINT_REGEX_CASE_INSENSITIVE"
+	LintNegationOnly           = "LINT_NEGATION_ONLY"
+	LintSetReplacesSubdocument = "LINT_SET_REPLACES_SUBDOCUMENT"
+	LintUnknownField           = "LINT_UNKNOWN_FIELD"
+	LintFieldTypeMismatch      = "LINT_FIELD_TYPE_MISMATCH"
+	LintNewTopLevelField       = "LINT_NEW_TOP_LEVEL_FIELD"
+	LintUnknownRule            = "LINT_UNKNOWN_RULE"
+	LintInvalidSeverity        = "LINT_INVALID_SEVERITY"
+
+	// Política de ejecución
+	PolicyConfirmationRequired = "POLICY_CONFIRMATION_REQUIRED"
+	PolicyInvalidConfirmation  = "POLICY_INVALID_CONFIRMATION"
+	PolicyProtectedDatabase    = "POLICY_PROTECTED_DATABASE"
+	PolicyProtectedCollection  = "POLICY_PROTECTED_COLLECTION"
+	PolicyTokenError           = "POLICY_TOKEN_ERROR"
+
+	// Autorización por API key
+	AuthCommandNotAllowed   = "AUTH_COMMAND_NOT_ALLOWED"
+	AuthNamespaceNotAllowed = "AUTH_NAMESPACE_NOT_ALLOWED"
+	AuthMissingPrincipal    = "AUTH_MISSING_PRINCIPAL"
+	AuthUnknownRole         = "AUTH_UNKNOWN_ROLE"
+	AuthUnknownCommand      = "AUTH_UNKNOWN_COMMAND"
+	AuthInvalidPattern      = "AUTH_INVALID_PATTERN"
+
+	// Sugerencias de corrección
+	FixInvalidCharacters  = "FIX_INVALID_CHARACTERS"
+	FixCheckSyntax        = "FIX_CHECK_SYNTAX"
+	FixAddDot             = "FIX_ADD_DOT"
+	FixAddParens          = "FIX_ADD_PARENS"
+	FixCloseParens        = "FIX_CLOSE_PARENS"
+	FixUseBraces          = "FIX_USE_BRACES"
+	FixCheckMongoSyntax   = "FIX_CHECK_MONGO_SYNTAX"
+	FixSyntaxIncorrect    = "FIX_SYNTAX_INCORRECT"
+	FixDatabaseName       = "FIX_DATABASE_NAME"
+	FixCollectionName     = "FIX_COLLECTION_NAME"
+	FixInsertDocument     = "FIX_INSERT_DOCUMENT"
+	FixDocumentStructure  = "FIX_DOCUMENT_STRUCTURE"
+	FixLintRule           = "FIX_LINT_RULE"
+	FixUpdateFilter       = "FIX_UPDATE_FILTER"
+	FixUpdateOperator     = "FIX_UPDATE_OPERATOR"
+	FixReplaceDocument    = "FIX_REPLACE_DOCUMENT"
+	FixJSONSchema         = "FIX_JSON_SCHEMA"
+	FixCheckLogic         = "FIX_CHECK_LOGIC"
+	FixQueryOperator      = "FIX_QUERY_OPERATOR"
+	FixUpdatePathConflict = "FIX_UPDATE_PATH_CONFLICT"
+	FixUpdatePipeline     = "FIX_UPDATE_PIPELINE"
+	SuggestDidYouMean     = "SUGGEST_DID_YOU_MEAN"
+
+	// Ejecución
+	ExecNotConnected        = "EXEC_NOT_CONNECTED"
+	ExecNoDatabase          = "EXEC_NO_DATABASE"
+	ExecNoDatabaseToDrop    = "EXEC_NO_DATABASE_TO_DROP"
+	ExecUnsupportedCommand  = "EXEC_UNSUPPORTED_COMMAND"
+	ExecTimeout             = "EXEC_TIMEOUT"
+	ExecCanceled            = "EXEC_CANCELED"
+	ExecCollectionExists    = "EXEC_COLLECTION_EXISTS"
+	ExecDuplicateKey        = "EXEC_DUPLICATE_KEY"
+	ExecUnsupportedOperator = "EXEC_UNSUPPORTED_OPERATOR"
+	ExecUpdateTypeMismatch  = "EXEC_UPDATE_TYPE_MISMATCH"
+	ExecImmutableID         = "EXEC_IMMUTABLE_ID"
+	ExecDatabaseSwitched    = "EXEC_DATABASE_SWITCHED"
+	ExecCollectionCreated   = "EXEC_COLLECTION_CREATED"
+	ExecDocumentInserted    = "EXEC_DOCUMENT_INSERTED"
+	ExecDocumentsFound      = "EXEC_DOCUMENTS_FOUND"
+	ExecDocumentsFirstBatch = "EXEC_DOCUMENTS_FIRST_BATCH"
+	ExecDocumentsNextBatch  = "EXEC_DOCUMENTS_NEXT_BATCH"
+	ExecDocumentsLastBatch  = "EXEC_DOCUMENTS_LAST_BATCH"
+	ExecDocumentsTruncated  = "EXEC_DOCUMENTS_TRUNCATED"
+	ExecUpdateCompleted     = "EXEC_UPDATE_COMPLETED"
+	ExecReplaceCompleted    = "EXEC_REPLACE_COMPLETED"
+	ExecDeleteCompleted     = "EXEC_DELETE_COMPLETED"
+	ExecCollectionDropped   = "EXEC_COLLECTION_DROPPED"
+	ExecDatabaseDropped     = "EXEC_DATABASE_DROPPED"
+
+	// Simulación (dry-run)
+	DryRunUseDatabase      = "DRY_RUN_USE_DATABASE"
+	DryRunCreateCollection = "DRY_RUN_CREATE_COLLECTION"
+	DryRunInsert           = "DRY_RUN_INSERT"
+	DryRunFind             = "DRY_RUN_FIND"
+	DryRunUpdate           = "DRY_RUN_UPDATE"
+	DryRunReplace          = "DRY_RUN_REPLACE"
+	DryRunDelete           = "DRY_RUN_DELETE"
+	DryRunDropCollection   = "DRY_RUN_DROP_COLLECTION"
+	DryRunDropDatabase     = "DRY_RUN_DROP_DATABASE"
+
+	// HTTP
+	HTTPInvalidJSON     = "HTTP_INVALID_JSON"
+	HTTPInvalidMode     = "HTTP_INVALID_MODE"
+	HTTPMissingCommand  = "HTTP_MISSING_COMMAND"
+	HTTPInvalidFormat   = "HTTP_INVALID_FORMAT"
+	HTTPNoParseTree     = "HTTP_NO_PARSE_TREE"
+	HTTPInvalidSchema   = "HTTP_INVALID_SCHEMA"
+	HTTPSchemaNotFound  = "HTTP_SCHEMA_NOT_FOUND"
+	HTTPInvalidLint     = "HTTP_INVALID_LINT"
+	HTTPInvalidAPIKey   = "HTTP_INVALID_API_KEY"
+	HTTPFeatureDisabled = "HTTP_FEATURE_DISABLED"
+
+	// Cursores
+	CursorNotFound = "CURSOR_NOT_FOUND"
+
+	// Exportación
+	ExportUnsupportedCommand = "EXPORT_UNSUPPORTED_COMMAND"
+	ExportSheetFull          = "EXPORT_SHEET_FULL"
+
+	// Importación
+	ImportInvalidType        = "IMPORT_INVALID_TYPE"
+	ImportInvalidValue       = "IMPORT_INVALID_VALUE"
+	ImportInvalidHeader      = "IMPORT_INVALID_HEADER"
+	ImportInvalidRow         = "IMPORT_INVALID_ROW"
+	ImportInvalidJSON        = "IMPORT_INVALID_JSON"
+	ImportMissingUpsertField = "IMPORT_MISSING_UPSERT_FIELD"
+	ImportMissingFile        = "IMPORT_MISSING_FILE"
+	ImportEmptyFile          = "IMPORT_EMPTY_FILE"
+)
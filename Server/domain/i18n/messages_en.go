@@ -0,0 +1,222 @@
+package i18n
+
+var messagesEN = map[string]string{
+	LexInvalidToken: "invalid token at position %d: '%s'",
+
+	ParseUnknownCommand:                 "unrecognized command: %s",
+	ParseExpectedDatabaseName:           "Expected a database name after 'use'",
+	ParseExpectedDotAfterDB:             "Expected '.' after 'db'",
+	ParseExpectedDotAfterCollection:     "Expected '.' after the collection name",
+	ParseExpectedFunction:               "Expected a function after '.'",
+	ParseUnknownFunction:                "Unrecognized function: %s",
+	ParseInvalidDBCommand:               "Invalid db command",
+	ParseExpectedLeftParen:              "Expected '(' after %s",
+	ParseExpectedRightParen:             "Expected ')' after %s",
+	ParseExpectedRightParenCollection:   "Expected ')' after the collection name",
+	ParseExpectedRightParenDocument:     "Expected ')' after the document",
+	ParseExpectedRightParenFilter:       "Expected ')' after the filter",
+	ParseExpectedCollectionString:       "Expected the collection name as a string",
+	ParseExpectedCommaFilterUpdate:      "Expected ',' between filter and update",
+	ParseFilterError:                    "Filter error: %s",
+	ParseUpdateError:                    "Update error: %s",
+	ParseExpectedCommaFilterReplacement: "Expected ',' between filter and replacement document",
+	ParseReplacementError:               "Replacement error: %s",
+	ParseExpectedLeftBrace:              "Expected '{' at the start of the document",
+	ParseExpectedIdentifierAfterDollar:  "Expected an identifier after '$'",
+	ParseExpectedKey:                    "Expected a string, identifier or $ operator as key",
+	ParseExpectedColon:                  "Expected ':' after the key",
+	ParseExpectedCommaOrRightBrace:      "Expected ',' or '}' in the document",
+	ParseExpectedCommaOrRightBracket:    "Expected ',' or ']' in the array",
+	ParseInvalidValue:                   "invalid value: %s",
+	ParseInvalidNumber:                  "invalid number: %s",
+	ParseTrailingTokens:                 "expected the end of the command, found '%s' at position %d",
+
+	SemInvalidSyntax:             "command is syntactically invalid",
+	SemDatabaseNameEmpty:         "the database name cannot be empty",
+	SemDatabaseNameChars:         "the database name contains invalid characters",
+	SemDatabaseNameTooLong:       "the database name is too long (63 bytes maximum)",
+	SemDatabaseNameNull:          "the database name cannot contain the null character",
+	SemDatabaseNameCaseClash:     "database '%s' differs only in case from the existing '%s'",
+	SemCollectionNameEmpty:       "the collection name cannot be empty",
+	SemCollectionNameDollar:      "the collection name cannot contain '$'",
+	SemCollectionNameNull:        "the collection name cannot contain the null character",
+	SemCollectionNameReserved:    "the 'system.' prefix is reserved: %s",
+	SemNamespaceTooLong:          "namespace '%s' is %d bytes long (maximum %d)",
+	SemInsertEmpty:               "the document to insert cannot be empty",
+	SemDocumentKeyEmpty:          "document keys cannot be empty",
+	SemDocumentKeyDollar:         "document keys cannot start with '$'",
+	SemFieldNameNull:             "field '%s' contains the null character",
+	SemFieldNameEmptySegment:     "field '%s' has an empty segment",
+	SemDocumentTooDeep:           "the document exceeds %d levels of nesting",
+	SemDocumentTooLarge:          "the document is about %d bytes, over the %d byte (16 MB) maximum",
+	SemUpdateFilterEmpty:         "the update filter cannot be empty",
+	SemUpdateEmpty:               "the update cannot be empty",
+	SemUpdateNoOperator:          "the update must contain at least one valid operator ($set, $unset, $inc, etc.)",
+	SemReplaceFilterEmpty:        "the replace filter cannot be empty",
+	SemReplaceOperator:           "the replacement document cannot contain operators (%s); use updateOne",
+	SemDeleteFilterEmpty:         "the delete filter cannot be empty",
+	SemUnknownQueryOperator:      "unknown query operator: %s",
+	SemOperatorNeedsField:        "the %s operator must be applied to a field, e.g. { field: { %s: value } }",
+	SemLogicalOperatorInField:    "the %s operator cannot be used inside the condition of field '%s'",
+	SemMixedOperatorsAndFields:   "the condition of field '%s' mixes operators and fields",
+	SemOptionsWithoutRegex:       "$options on field '%s' requires $regex",
+	SemUnknownUpdateOperator:     "unknown update operator: %s",
+	SemUpdateMixedFields:         "the update document mixes operators with field '%s'; use { $set: { ... } }",
+	SemUpdateOperatorEmpty:       "the %s operator requires a non-empty document of fields",
+	SemUnknownUpdateModifier:     "the %s modifier is not valid in %s",
+	SemUpdateModifierWithoutEach: "the modifiers of '%s' require $each",
+	SemUpdatePathConflict:        "paths '%s' and '%s' are updated together and conflict",
+	SemUpdatePipelineEmpty:       "the update pipeline cannot be empty",
+	SemUpdatePipelineStageShape:  "each update pipeline stage must be a document with a single operator",
+	SemUpdatePipelineStage:       "stage not allowed in an update pipeline: %s",
+	SemSchemaType:                "%s must be %s, got %s",
+	SemSchemaRequired:            "missing required field %s",
+	SemSchemaAdditional:          "field %s is not allowed by the schema",
+	SemSchemaEnum:                "%s must be one of: %s",
+	SemSchemaMinimum:             "%s must be %s %s",
+	SemSchemaMaximum:             "%s must be %s %s",
+	SemSchemaMultipleOf:          "%s must be a multiple of %s",
+	SemSchemaMinLength:           "%s must have at least %d characters",
+	SemSchemaMaxLength:           "%s must have at most %d characters",
+	SemSchemaPattern:             "%s does not match the pattern %s",
+	SemSchemaMinItems:            "%s must have at least %d items",
+	SemSchemaMaxItems:            "%s must have at most %d items",
+	SemSchemaUniqueItems:         "%s cannot contain duplicate items",
+	SemSchemaMinProperties:       "%s must have at least %d fields",
+	SemSchemaMaxProperties:       "%s must have at most %d fields",
+	SemSchemaAnyOf:               "%s does not match any anyOf alternative",
+	SemSchemaOneOf:               "%s must match exactly one oneOf alternative",
+	SemSchemaNot:                 "%s must not match the not schema",
+	SemOperandType:               "the operand of %s on '%s' must be %s",
+	TypeString:                   "a string",
+	TypeDocument:                 "a document",
+	TypeArray:                    "an array",
+	TypeBoolean:                  "a boolean",
+	TypeNonNegativeInteger:       "a non-negative integer",
+	TypeBSONType:                 "a valid BSON type alias or number",
+	TypeRegexOptions:             "a string with the options i, m, s, x or u",
+	TypeModOperand:               "an array [divisor, remainder] with a non-zero divisor",
+	TypeFilterArray:              "a non-empty array of filter documents",
+	TypeOperatorDocument:         "an operator document",
+	TypeNumber:                   "a number",
+	TypeInteger:                  "an integer",
+	TypeRenameTarget:             "a string with a different field name",
+	TypeCurrentDate:              "true or { $type: \"date\" | \"timestamp\" }",
+	TypePopDirection:             "1 or -1",
+	TypeSortSpec:                 "1, -1 or a document { field: 1 | -1 }",
+	TypeFieldList:                "a field name or an array of names",
+	SchemaRootDocument:           "the document",
+
+	AnalyzeLexicalError:  "Lexical error: %s",
+	AnalyzeSyntaxError:   "Syntax error: %s",
+	AnalyzeSemanticError: "Semantic error: %s",
+	AnalyzeLintError:     "Rule %s: %s",
+
+	LintFindWithoutFilter:      "find() without a filter scans the whole %s collection",
+	LintServerSideJavaScript:   "%s runs JavaScript on the server: it is slow and may be disabled",
+	LintRegexUnanchored:        "the regular expression on '%s' (%s) is not anchored with ^ and cannot use indexes efficiently",
+	LintRegexCaseInsensitive:   "the regular expression on '%s' is case-insensitive and cannot use indexes efficiently",
+	LintNegationOnly:           "the filter only uses $ne/$nin, which are not selective and scan most of the collection",
+	LintSetReplacesSubdocument: "$set on '%s' replaces the whole subdocument; use dotted paths to change only some fields",
+	LintUnknownField:           "field '%s' does not appear in any of the %d sampled documents of %s; is it spelled correctly?",
+	LintFieldTypeMismatch:      "'%s' is compared with a %s value, but in the sample it is %s",
+	LintNewTopLevelField:       "the update adds field '%s', which does not appear in the sample of %s",
+	LintUnknownRule:            "unknown lint rule: %s (available: %s)",
+	LintInvalidSeverity:        "invalid severity '%s' for rule %s (off, info, warning, error)",
+
+	PolicyConfirmationRequired: "%s is destructive: repeat the request with confirmationToken to confirm it",
+	PolicyInvalidConfirmation:  "the confirmation token is invalid, expired, belongs to another command or was issued to another client; a new one has been issued",
+	PolicyProtectedDatabase:    "database '%s' is protected and cannot be dropped",
+	PolicyProtectedCollection:  "collection '%s' is protected and cannot be dropped",
+	PolicyTokenError:           "could not generate the confirmation token: %s",
+
+	AuthCommandNotAllowed:   "role '%s' is not allowed to run %s",
+	AuthNamespaceNotAllowed: "role '%s' has no access to '%s'",
+	AuthMissingPrincipal:    "an API key is required to run %s",
+	AuthUnknownRole:         "API key '%s' uses unknown role '%s'",
+	AuthUnknownCommand:      "role '%s' lists unknown command '%s'",
+	AuthInvalidPattern:      "role '%s' lists invalid pattern '%s'",
+
+	FixInvalidCharacters:  "Check for special characters. Correct example: db.users.find()",
+	FixCheckSyntax:        "Check the command syntax",
+	FixAddDot:             "Add a dot after 'db': db.collectionName.function()",
+	FixAddParens:          "Add parentheses after the function: function()",
+	FixCloseParens:        "Close the parentheses: function(...)",
+	FixUseBraces:          "Use braces for objects: { field: value }",
+	FixCheckMongoSyntax:   "Check the MongoDB command syntax",
+	FixSyntaxIncorrect:    "Command is syntactically incorrect",
+	FixDatabaseName:       "Use a valid database name (no special characters)",
+	FixCollectionName:     "Use a valid collection name (no '$', null character or 'system.' prefix)",
+	FixInsertDocument:     "The document must have at least one field: { field: value }",
+	FixDocumentStructure:  "Check the field names (no empty segments or null characters) and reduce the nesting or size of the document",
+	FixLintRule:           "Fix the pattern or disable it with // analyzer-disable-next-line %s",
+	FixUpdateFilter:       "Specify a filter: { field: value }",
+	FixUpdateOperator:     "Use operators such as $set: { $set: { field: newValue } }",
+	FixReplaceDocument:    "Use a full document without operators: db.collection.replaceOne({ _id: 1 }, { name: \"value\" })",
+	FixJSONSchema:         "Adjust the document to the collection's $jsonSchema",
+	FixCheckLogic:         "Check the command logic",
+	FixQueryOperator:      "Check the filter operators: { field: { $gt: 5 } }, { $or: [ { a: 1 }, { b: 2 } ] }",
+	FixUpdatePathConflict: "Split the update so a path and its subfields are not modified together",
+	FixUpdatePipeline:     "Use $set, $addFields, $project, $unset, $replaceRoot or $replaceWith stages: [ { $set: { field: value } } ]",
+	SuggestDidYouMean:     "Did you mean '%s' instead of '%s'?",
+
+	ExecNotConnected:        "not connected to MongoDB",
+	ExecNoDatabase:          "no database selected. Run 'use dbName' first",
+	ExecNoDatabaseToDrop:    "no database specified or selected. Run 'use dbName' first or specify the database",
+	ExecUnsupportedCommand:  "unsupported command type",
+	ExecTimeout:             "the operation exceeded the maximum allowed time",
+	ExecCanceled:            "the operation was canceled by the client",
+	ExecCollectionExists:    "collection '%s' already exists",
+	ExecDuplicateKey:        "a document with _id %v already exists in '%s'",
+	ExecUnsupportedOperator: "%s is not supported by the in-memory backend",
+	ExecUpdateTypeMismatch:  "cannot apply %s to field '%s' of type %s",
+	ExecImmutableID:         "the _id field cannot be modified",
+	ExecDatabaseSwitched:    "Switched to database '%s'",
+	ExecCollectionCreated:   "Collection '%s' created successfully",
+	ExecDocumentInserted:    "Document inserted successfully",
+	ExecDocumentsFound:      "Found %d documents",
+	ExecDocumentsFirstBatch: "Returned the first %d documents; request more with POST /cursor/%s/next",
+	ExecDocumentsNextBatch:  "Returned %d more documents (%d in total); request more with POST /cursor/%s/next",
+	ExecDocumentsLastBatch:  "Returned the last %d documents (%d in total)",
+	ExecDocumentsTruncated:  "Returned %d documents in total: the per-query maximum was reached, use a more selective filter",
+	ExecUpdateCompleted:     "Update completed",
+	ExecReplaceCompleted:    "Replace completed",
+	ExecDeleteCompleted:     "Delete completed",
+	ExecCollectionDropped:   "Collection '%s' dropped successfully",
+	ExecDatabaseDropped:     "Database '%s' dropped successfully",
+
+	DryRunUseDatabase:      "Dry run: would switch to database '%s'",
+	DryRunCreateCollection: "Dry run: would create collection '%s'",
+	DryRunInsert:           "Dry run: would insert 1 document into '%s'",
+	DryRunFind:             "Dry run: %d documents match the filter",
+	DryRunUpdate:           "Dry run: would update %d documents",
+	DryRunReplace:          "Dry run: would replace %d documents",
+	DryRunDelete:           "Dry run: would delete %d documents",
+	DryRunDropCollection:   "Dry run: would drop collection '%s' with %d documents",
+	DryRunDropDatabase:     "Dry run: would drop database '%s' with %d collections",
+
+	HTTPInvalidJSON:     "Invalid JSON",
+	HTTPInvalidMode:     "Invalid mode: %s (use validate, dry-run or execute)",
+	HTTPMissingCommand:  "Missing 'command' parameter",
+	HTTPInvalidFormat:   "Invalid format: %s (use %s)",
+	HTTPNoParseTree:     "Could not build the parse tree: %s",
+	HTTPInvalidSchema:   "The schema must be a JSON document",
+	HTTPSchemaNotFound:  "Collection %s has no $jsonSchema",
+	HTTPInvalidLint:     "Invalid lint configuration: %s",
+	HTTPInvalidAPIKey:   "Missing or invalid API key",
+	HTTPFeatureDisabled: "Feature %s is disabled on this server",
+
+	CursorNotFound: "Cursor %s does not exist or has expired",
+
+	ExportUnsupportedCommand: "Only find commands can be exported; got %s",
+	ExportSheetFull:          "A spreadsheet holds at most %d rows; use csv or ndjson to export more",
+
+	ImportInvalidType:        "Unknown type for %s: %s (use string, int, long, double, bool, date or objectId)",
+	ImportInvalidValue:       "%s: %q is not a valid %s value",
+	ImportInvalidHeader:      "Invalid CSV header: %s",
+	ImportInvalidRow:         "Invalid row: %v",
+	ImportInvalidJSON:        "The file is not valid JSON: %v",
+	ImportMissingUpsertField: "The document lacks the identifying field %s",
+	ImportMissingFile:        "Missing file: send it as the request body or in the file field of a form",
+	ImportEmptyFile:          "The file is empty",
+}
@@ -0,0 +1,222 @@
+package i18n
+
+var messagesES = map[string]string{
+	LexInvalidToken: "token inválido en posición %d: '%s'",
+
+	ParseUnknownCommand:                 "comando no reconocido: %s",
+	ParseExpectedDatabaseName:           "Se esperaba nombre de base de datos después de 'use'",
+	ParseExpectedDotAfterDB:             "Se esperaba '.' después de 'db'",
+	ParseExpectedDotAfterCollection:     "Se esperaba '.' después del nombre de la colección",
+	ParseExpectedFunction:               "Se esperaba función después de '.'",
+	ParseUnknownFunction:                "Función no reconocida: %s",
+	ParseInvalidDBCommand:               "Comando db inválido",
+	ParseExpectedLeftParen:              "Se esperaba '(' después de %s",
+	ParseExpectedRightParen:             "Se esperaba ')' después de %s",
+	ParseExpectedRightParenCollection:   "Se esperaba ')' después del nombre de la colección",
+	ParseExpectedRightParenDocument:     "Se esperaba ')' después del documento",
+	ParseExpectedRightParenFilter:       "Se esperaba ')' después del filtro",
+	ParseExpectedCollectionString:       "Se esperaba nombre de colección como string",
+	ParseExpectedCommaFilterUpdate:      "Se esperaba ',' entre filtro y actualización",
+	ParseFilterError:                    "Error en filtro: %s",
+	ParseUpdateError:                    "Error en actualización: %s",
+	ParseExpectedCommaFilterReplacement: "Se esperaba ',' entre filtro y documento de reemplazo",
+	ParseReplacementError:               "Error en documento de reemplazo: %s",
+	ParseExpectedLeftBrace:              "Se esperaba '{' al inicio del documento",
+	ParseExpectedIdentifierAfterDollar:  "Se esperaba identificador después de '$'",
+	ParseExpectedKey:                    "Se esperaba string, identificador u operador $ como clave",
+	ParseExpectedColon:                  "Se esperaba ':' después de la clave",
+	ParseExpectedCommaOrRightBrace:      "Se esperaba ',' o '}' en el documento",
+	ParseExpectedCommaOrRightBracket:    "Se esperaba ',' o ']' en el arreglo",
+	ParseInvalidValue:                   "valor no válido: %s",
+	ParseInvalidNumber:                  "número no válido: %s",
+	ParseTrailingTokens:                 "se esperaba el final del comando, se encontró '%s' en la posición %d",
+
+	SemInvalidSyntax:             "comando sintácticamente inválido",
+	SemDatabaseNameEmpty:         "el nombre de la base de datos no puede estar vacío",
+	SemDatabaseNameChars:         "el nombre de la base de datos contiene caracteres inválidos",
+	SemDatabaseNameTooLong:       "el nombre de la base de datos es demasiado largo (máximo 63 bytes)",
+	SemDatabaseNameNull:          "el nombre de la base de datos no puede contener el carácter nulo",
+	SemDatabaseNameCaseClash:     "la base de datos '%s' solo difiere en mayúsculas de la existente '%s'",
+	SemCollectionNameEmpty:       "el nombre de la colección no puede estar vacío",
+	SemCollectionNameDollar:      "el nombre de la colección no puede contener '$'",
+	SemCollectionNameNull:        "el nombre de la colección no puede contener el carácter nulo",
+	SemCollectionNameReserved:    "el prefijo 'system.' está reservado: %s",
+	SemNamespaceTooLong:          "el espacio de nombres '%s' ocupa %d bytes (máximo %d)",
+	SemInsertEmpty:               "el documento a insertar no puede estar vacío",
+	SemDocumentKeyEmpty:          "las claves del documento no pueden estar vacías",
+	SemDocumentKeyDollar:         "las claves del documento no pueden comenzar con '$'",
+	SemFieldNameNull:             "el campo '%s' contiene el carácter nulo",
+	SemFieldNameEmptySegment:     "el campo '%s' tiene un segmento vacío",
+	SemDocumentTooDeep:           "el documento supera los %d niveles de anidamiento",
+	SemDocumentTooLarge:          "el documento ocupa unos %d bytes y supera el máximo de %d (16 MB)",
+	SemUpdateFilterEmpty:         "el filtro de actualización no puede estar vacío",
+	SemUpdateEmpty:               "la actualización no puede estar vacía",
+	SemUpdateNoOperator:          "la actualización debe contener al menos un operador válido ($set, $unset, $inc, etc.)",
+	SemReplaceFilterEmpty:        "el filtro de reemplazo no puede estar vacío",
+	SemReplaceOperator:           "el documento de reemplazo no puede contener operadores (%s); use updateOne",
+	SemDeleteFilterEmpty:         "el filtro de eliminación no puede estar vacío",
+	SemUnknownQueryOperator:      "operador de consulta desconocido: %s",
+	SemOperatorNeedsField:        "el operador %s debe aplicarse a un campo, por ejemplo { campo: { %s: valor } }",
+	SemLogicalOperatorInField:    "el operador %s no puede usarse dentro de la condición del campo '%s'",
+	SemMixedOperatorsAndFields:   "la condición del campo '%s' mezcla operadores con campos",
+	SemOptionsWithoutRegex:       "$options en el campo '%s' requiere $regex",
+	SemUnknownUpdateOperator:     "operador de actualización desconocido: %s",
+	SemUpdateMixedFields:         "el documento de actualización mezcla operadores con el campo '%s'; use { $set: { ... } }",
+	SemUpdateOperatorEmpty:       "el operador %s requiere un documento no vacío de campos",
+	SemUnknownUpdateModifier:     "el modificador %s no es válido en %s",
+	SemUpdateModifierWithoutEach: "los modificadores de '%s' requieren $each",
+	SemUpdatePathConflict:        "las rutas '%s' y '%s' se modifican a la vez y entran en conflicto",
+	SemUpdatePipelineEmpty:       "el pipeline de actualización no puede estar vacío",
+	SemUpdatePipelineStageShape:  "cada etapa del pipeline de actualización debe ser un documento con un único operador",
+	SemUpdatePipelineStage:       "etapa no permitida en un pipeline de actualización: %s",
+	SemSchemaType:                "%s debe ser %s, se obtuvo %s",
+	SemSchemaRequired:            "falta el campo obligatorio %s",
+	SemSchemaAdditional:          "el campo %s no está permitido por el esquema",
+	SemSchemaEnum:                "%s debe ser uno de: %s",
+	SemSchemaMinimum:             "%s debe ser %s %s",
+	SemSchemaMaximum:             "%s debe ser %s %s",
+	SemSchemaMultipleOf:          "%s debe ser múltiplo de %s",
+	SemSchemaMinLength:           "%s debe tener al menos %d caracteres",
+	SemSchemaMaxLength:           "%s debe tener como máximo %d caracteres",
+	SemSchemaPattern:             "%s no cumple el patrón %s",
+	SemSchemaMinItems:            "%s debe tener al menos %d elementos",
+	SemSchemaMaxItems:            "%s debe tener como máximo %d elementos",
+	SemSchemaUniqueItems:         "%s no puede tener elementos repetidos",
+	SemSchemaMinProperties:       "%s debe tener al menos %d campos",
+	SemSchemaMaxProperties:       "%s debe tener como máximo %d campos",
+	SemSchemaAnyOf:               "%s no cumple ninguna de las alternativas de anyOf",
+	SemSchemaOneOf:               "%s debe cumplir exactamente una alternativa de oneOf",
+	SemSchemaNot:                 "%s no debe cumplir el esquema de not",
+	SemOperandType:               "el operando de %s en '%s' debe ser %s",
+	TypeString:                   "una cadena",
+	TypeDocument:                 "un documento",
+	TypeArray:                    "un array",
+	TypeBoolean:                  "un booleano",
+	TypeNonNegativeInteger:       "un entero no negativo",
+	TypeBSONType:                 "un alias o número de tipo BSON válido",
+	TypeRegexOptions:             "una cadena con las opciones i, m, s, x o u",
+	TypeModOperand:               "un array [divisor, resto] con divisor distinto de cero",
+	TypeFilterArray:              "un array no vacío de documentos de filtro",
+	TypeOperatorDocument:         "un documento de operadores",
+	TypeNumber:                   "un número",
+	TypeInteger:                  "un entero",
+	TypeRenameTarget:             "una cadena con un nombre de campo distinto",
+	TypeCurrentDate:              "true o { $type: \"date\" | \"timestamp\" }",
+	TypePopDirection:             "1 o -1",
+	TypeSortSpec:                 "1, -1 o un documento { campo: 1 | -1 }",
+	TypeFieldList:                "un nombre de campo o un array de nombres",
+	SchemaRootDocument:           "el documento",
+
+	AnalyzeLexicalError:  "Error léxico: %s",
+	AnalyzeSyntaxError:   "Error sintáctico: %s",
+	AnalyzeSemanticError: "Error semántico: %s",
+	AnalyzeLintError:     "Regla %s: %s",
+
+	LintFindWithoutFilter:      "find() sin filtro recorre toda la colección %s",
+	LintServerSideJavaScript:   "%s ejecuta JavaScript en el servidor: es lento y puede estar deshabilitado",
+	LintRegexUnanchored:        "la expresión regular de '%s' (%s) no está anclada con ^ y no aprovecha los índices",
+	LintRegexCaseInsensitive:   "la expresión regular de '%s' no distingue mayúsculas y no aprovecha los índices",
+	LintNegationOnly:           "el filtro solo usa $ne/$nin, que no son selectivos y recorren casi toda la colección",
+	LintSetReplacesSubdocument: "$set sobre '%s' reemplaza el subdocumento completo; use rutas con punto para modificar solo algunos campos",
+	LintUnknownField:           "el campo '%s' no aparece en ninguno de los %d documentos muestreados de %s; ¿está bien escrito?",
+	LintFieldTypeMismatch:      "'%s' se compara con un valor de tipo %s, pero en la muestra es %s",
+	LintNewTopLevelField:       "la actualización añade el campo '%s', que no aparece en la muestra de %s",
+	LintUnknownRule:            "regla de lint desconocida: %s (disponibles: %s)",
+	LintInvalidSeverity:        "nivel inválido '%s' para la regla %s (off, info, warning, error)",
+
+	PolicyConfirmationRequired: "%s es destructivo: repita la petición con confirmationToken para confirmarlo",
+	PolicyInvalidConfirmation:  "el token de confirmación no es válido, ha caducado, corresponde a otro comando o se emitió para otro cliente; se ha emitido uno nuevo",
+	PolicyProtectedDatabase:    "la base de datos '%s' está protegida y no se puede eliminar",
+	PolicyProtectedCollection:  "la colección '%s' está protegida y no se puede eliminar",
+	PolicyTokenError:           "no se pudo generar el token de confirmación: %s",
+
+	AuthCommandNotAllowed:   "el rol '%s' no permite ejecutar %s",
+	AuthNamespaceNotAllowed: "el rol '%s' no tiene acceso a '%s'",
+	AuthMissingPrincipal:    "se requiere una API key para ejecutar %s",
+	AuthUnknownRole:         "la API key '%s' usa el rol desconocido '%s'",
+	AuthUnknownCommand:      "el rol '%s' incluye el comando desconocido '%s'",
+	AuthInvalidPattern:      "el rol '%s' incluye el patrón inválido '%s'",
+
+	FixInvalidCharacters:  "Verifica caracteres especiales. Ejemplo correcto: db.usuarios.find()",
+	FixCheckSyntax:        "Revisa la sintaxis del comando",
+	FixAddDot:             "Agrega un punto después de 'db': db.nombreColeccion.funcion()",
+	FixAddParens:          "Agrega paréntesis después de la función: funcion()",
+	FixCloseParens:        "Cierra los paréntesis: funcion(...)",
+	FixUseBraces:          "Usa llaves para objetos: { campo: valor }",
+	FixCheckMongoSyntax:   "Revisa la sintaxis del comando MongoDB",
+	FixSyntaxIncorrect:    "Comando sintácticamente incorrecto",
+	FixDatabaseName:       "Usa un nombre válido para la base de datos (sin caracteres especiales)",
+	FixCollectionName:     "Usa un nombre válido para la colección (sin '$', carácter nulo ni prefijo 'system.')",
+	FixInsertDocument:     "El documento debe tener al menos un campo: { campo: valor }",
+	FixDocumentStructure:  "Revise los nombres de campo (sin segmentos vacíos ni carácter nulo) y reduzca el anidamiento o el tamaño del documento",
+	FixLintRule:           "Corrija el patrón o desactívelo con // analyzer-disable-next-line %s",
+	FixUpdateFilter:       "Especifica un filtro: { campo: valor }",
+	FixUpdateOperator:     "Usa operadores como $set: { $set: { campo: nuevoValor } }",
+	FixReplaceDocument:    "Use un documento completo sin operadores: db.coleccion.replaceOne({ _id: 1 }, { nombre: \"valor\" })",
+	FixJSONSchema:         "Ajuste el documento al $jsonSchema de la colección",
+	FixCheckLogic:         "Revisa la lógica del comando",
+	FixQueryOperator:      "Revise los operadores del filtro: { campo: { $gt: 5 } }, { $or: [ { a: 1 }, { b: 2 } ] }",
+	FixUpdatePathConflict: "Divida la actualización para no modificar a la vez una ruta y sus subcampos",
+	FixUpdatePipeline:     "Use etapas $set, $addFields, $project, $unset, $replaceRoot o $replaceWith: [ { $set: { campo: valor } } ]",
+	SuggestDidYouMean:     "¿Quisiste decir '%s' en lugar de '%s'?",
+
+	ExecNotConnected:        "no hay conexión a MongoDB",
+	ExecNoDatabase:          "no hay base de datos seleccionada. Usa 'use nombreDB' primero",
+	ExecNoDatabaseToDrop:    "no hay base de datos especificada o seleccionada. Usa 'use nombreDB' primero o especifica la base de datos",
+	ExecUnsupportedCommand:  "tipo de comando no soportado",
+	ExecTimeout:             "la operación superó el tiempo máximo permitido",
+	ExecCanceled:            "la operación fue cancelada por el cliente",
+	ExecCollectionExists:    "la colección '%s' ya existe",
+	ExecDuplicateKey:        "ya existe un documento con _id %v en '%s'",
+	ExecUnsupportedOperator: "%s no está soportado por el backend en memoria",
+	ExecUpdateTypeMismatch:  "no se puede aplicar %s al campo '%s' de tipo %s",
+	ExecImmutableID:         "el campo _id no se puede modificar",
+	ExecDatabaseSwitched:    "Cambiado a base de datos '%s'",
+	ExecCollectionCreated:   "Colección '%s' creada exitosamente",
+	ExecDocumentInserted:    "Documento insertado exitosamente",
+	ExecDocumentsFound:      "Encontrados %d documentos",
+	ExecDocumentsFirstBatch: "Devueltos los primeros %d documentos; pide los siguientes con POST /cursor/%s/next",
+	ExecDocumentsNextBatch:  "Devueltos %d documentos más (%d en total); pide los siguientes con POST /cursor/%s/next",
+	ExecDocumentsLastBatch:  "Devueltos los últimos %d documentos (%d en total)",
+	ExecDocumentsTruncated:  "Devueltos %d documentos en total: se alcanzó el máximo por consulta, usa un filtro más selectivo",
+	ExecUpdateCompleted:     "Actualización completada",
+	ExecReplaceCompleted:    "Reemplazo completado",
+	ExecDeleteCompleted:     "Eliminación completada",
+	ExecCollectionDropped:   "Colección '%s' eliminada exitosamente",
+	ExecDatabaseDropped:     "Base de datos '%s' eliminada exitosamente",
+
+	DryRunUseDatabase:      "Simulación: se cambiaría a la base de datos '%s'",
+	DryRunCreateCollection: "Simulación: se crearía la colección '%s'",
+	DryRunInsert:           "Simulación: se insertaría 1 documento en '%s'",
+	DryRunFind:             "Simulación: %d documentos coinciden con el filtro",
+	DryRunUpdate:           "Simulación: se actualizarían %d documentos",
+	DryRunReplace:          "Simulación: se reemplazarían %d documentos",
+	DryRunDelete:           "Simulación: se eliminarían %d documentos",
+	DryRunDropCollection:   "Simulación: se eliminaría la colección '%s' con %d documentos",
+	DryRunDropDatabase:     "Simulación: se eliminaría la base de datos '%s' con %d colecciones",
+
+	HTTPInvalidJSON:     "JSON inválido",
+	HTTPInvalidMode:     "Modo inválido: %s (usa validate, dry-run o execute)",
+	HTTPMissingCommand:  "Falta el parámetro 'command'",
+	HTTPInvalidFormat:   "Formato inválido: %s (usa %s)",
+	HTTPNoParseTree:     "No se pudo construir el árbol: %s",
+	HTTPInvalidSchema:   "El esquema debe ser un documento JSON",
+	HTTPSchemaNotFound:  "La colección %s no tiene $jsonSchema",
+	HTTPInvalidLint:     "Configuración de lint inválida: %s",
+	HTTPInvalidAPIKey:   "API key ausente o no válida",
+	HTTPFeatureDisabled: "La función %s está desactivada en este servidor",
+
+	CursorNotFound: "El cursor %s no existe o ha caducado",
+
+	ExportUnsupportedCommand: "Solo se pueden exportar comandos find; se recibió %s",
+	ExportSheetFull:          "La hoja de cálculo admite como máximo %d filas; usa csv o ndjson para exportar más",
+
+	ImportInvalidType:        "Tipo desconocido para %s: %s (usa string, int, long, double, bool, date u objectId)",
+	ImportInvalidValue:       "%s: %q no es un valor %s válido",
+	ImportInvalidHeader:      "Cabecera CSV inválida: %s",
+	ImportInvalidRow:         "Fila inválida: %v",
+	ImportInvalidJSON:        "El archivo no es JSON válido: %v",
+	ImportMissingUpsertField: "El documento no tiene el campo %s que lo identifica",
+	ImportMissingFile:        "Falta el archivo: envíalo como cuerpo de la petición o en el campo file de un formulario",
+	ImportEmptyFile:          "El archivo está vacío",
+}
@@ -1,7 +1,11 @@
 package interfaces
 
-import "mongo-analyzer/domain/entities"
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
 
 type CommandAnalyzer interface {
-	Analyze(input string) (*entities.AnalysisResult, error)
+	Analyze(ctx context.Context, input string, options entities.AnalysisOptions) (*entities.AnalysisResult, error)
 }
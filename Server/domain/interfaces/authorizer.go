@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// Authorizer comprueba que el principal de la petición (en ctx) puede
+// ejecutar el comando. Devuelve nil si está permitido.
+type Authorizer interface {
+	Authorize(ctx context.Context, command *entities.MongoCommand) *entities.AuthorizationError
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+// NamespaceCatalog expone los nombres reales del servidor conectado.
+type NamespaceCatalog interface {
+	ListDatabases(ctx context.Context) ([]string, error)
+	ListCollections(ctx context.Context) ([]string, error)
+	ListFields(ctx context.Context, collection string) ([]string, error)
+}
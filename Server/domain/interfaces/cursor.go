@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// DocumentIterator recorre el resultado de una consulta sin cargarlo
+// entero en memoria.
+type DocumentIterator interface {
+	// Next devuelve hasta size documentos; menos de size significa que no
+	// quedan más.
+	Next(ctx context.Context, size int) ([]map[string]interface{}, error)
+	Close(ctx context.Context) error
+}
+
+// CursorRegistry guarda los cursores abiertos entre peticiones.
+type CursorRegistry interface {
+	// Open lee el primer lote de iterator y, si quedan documentos, guarda el
+	// cursor para pedir los siguientes. Si no, lo cierra.
+	Open(ctx context.Context, iterator DocumentIterator, database, collection string) (*entities.CursorPage, error)
+}
+
+// DocumentStreamer abre un iterador sobre los documentos de un FIND sin
+// pasar por el registro de cursores: quien lo pide lo recorre y lo cierra.
+type DocumentStreamer interface {
+	Stream(ctx context.Context, command *entities.MongoCommand) (DocumentIterator, error)
+}
@@ -1,9 +1,23 @@
 package interfaces
 
-import "mongo-analyzer/domain/entities"
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
 
 type MongoExecutor interface {
-	Execute(command *entities.MongoCommand) (interface{}, error)
+	Execute(ctx context.Context, command *entities.MongoCommand) (interface{}, error)
+	DryRun(ctx context.Context, command *entities.MongoCommand) (interface{}, error)
+	// CurrentDatabase devuelve la base de datos seleccionada con use, o ""
+	CurrentDatabase(ctx context.Context) string
 	Connect() error
 	Close() error
-}
\ No newline at end of file
+}
+
+// BatchWriter escribe un lote de documentos en una colección. Los errores
+// de cada documento van en su WriteOutcome y no detienen el resto del lote;
+// el error devuelto indica que el lote entero falló.
+type BatchWriter interface {
+	WriteBatch(ctx context.Context, database, collection string, writes []entities.BatchWrite) ([]entities.WriteOutcome, error)
+}
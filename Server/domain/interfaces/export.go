@@ -0,0 +1,12 @@
+package interfaces
+
+// ExportWriter escribe documentos en un formato de exportación a medida que
+// llegan, sin acumularlos.
+type ExportWriter interface {
+	Write(document map[string]interface{}) error
+	// Flush envía lo que el formato tenga pendiente; se llama tras cada lote.
+	Flush() error
+	// Close escribe el final del formato (el cierre del array, el pie de la
+	// hoja...) sin cerrar el destino.
+	Close() error
+}
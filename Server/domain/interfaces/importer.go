@@ -0,0 +1,10 @@
+package interfaces
+
+import "mongo-analyzer/domain/entities"
+
+// RowReader lee los documentos de un archivo de importación. Devuelve
+// io.EOF al terminar. Un error de una sola fila va en ImportRow.Err; el
+// error devuelto indica que el archivo no se puede seguir leyendo.
+type RowReader interface {
+	Next() (*entities.ImportRow, error)
+}
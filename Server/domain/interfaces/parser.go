@@ -1,7 +1,11 @@
 package interfaces
 
-import "mongo-analyzer/domain/entities"
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
 
 type Parser interface {
-	Parse(tokens []*entities.Token) (*entities.MongoCommand, error)
+	Parse(ctx context.Context, tokens []*entities.Token) (*entities.MongoCommand, error)
 }
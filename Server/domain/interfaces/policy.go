@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// CommandPolicy decide si un comando ya validado puede ejecutarse.
+// confirmation es el token que el cliente reenvía para confirmar un
+// comando destructivo, o "" en la primera petición.
+type CommandPolicy interface {
+	Authorize(ctx context.Context, command *entities.MongoCommand, confirmation string) *entities.PolicyDecision
+}
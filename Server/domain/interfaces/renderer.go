@@ -0,0 +1,8 @@
+package interfaces
+
+import "mongo-analyzer/domain/entities"
+
+type TreeRenderer interface {
+	Render(root *entities.ASTNode) (string, error)
+	ContentType() string
+}
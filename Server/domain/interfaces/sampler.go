@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// DocumentSampler devuelve hasta size documentos elegidos al azar de una
+// colección.
+type DocumentSampler interface {
+	SampleDocuments(ctx context.Context, database, collection string, size int) ([]map[string]interface{}, error)
+}
+
+// SchemaInferrer devuelve el esquema inferido de una colección.
+type SchemaInferrer interface {
+	InferSchema(ctx context.Context, database, collection string) (*entities.InferredSchema, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "context"
+
+// SchemaSource devuelve el $jsonSchema que deben cumplir los documentos de
+// una colección de database, o nil si la colección no tiene esquema.
+type SchemaSource interface {
+	CollectionSchema(ctx context.Context, database, collection string) (map[string]interface{}, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+type Suggester interface {
+	SuggestFunction(name string) (string, bool)
+	SuggestOperator(name string) (string, bool)
+	SuggestCollection(ctx context.Context, name string) (string, bool)
+	SuggestField(ctx context.Context, collection, name string) (string, bool)
+}
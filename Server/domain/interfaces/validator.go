@@ -1,7 +1,14 @@
 package interfaces
 
-import "mongo-analyzer/domain/entities"
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+)
 
 type Validator interface {
-	ValidateSemantics(command *entities.MongoCommand) error
+	ValidateSemantics(ctx context.Context, command *entities.MongoCommand) error
+	// Lint devuelve los patrones de riesgo del comando; overrides cambia el
+	// nivel de las reglas para esta llamada.
+	Lint(ctx context.Context, command *entities.MongoCommand, overrides entities.LintConfig) []entities.LintFinding
 }
@@ -5,6 +5,7 @@ go 1.24.3
 require (
 	github.com/gorilla/mux v1.8.0
 	go.mongodb.org/mongo-driver v1.13.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/infrastructure/cursor"
+	"mongo-analyzer/infrastructure/importer"
+	"mongo-analyzer/infrastructure/policy"
+	"mongo-analyzer/infrastructure/session"
+	"mongo-analyzer/infrastructure/validator"
+)
+
+// Config es la configuración del servidor. Se construye, por orden de
+// prioridad creciente, con los valores por defecto, el archivo YAML
+// indicado con -config (o ANALYZER_CONFIG), las variables de entorno
+// ANALYZER_* y las opciones de la línea de comandos.
+type Config struct {
+	Listen     string          `yaml:"listen"`
+	Backend    string          `yaml:"backend"`
+	TLS        TLSConfig       `yaml:"tls"`
+	Mongo      MongoConfig     `yaml:"mongo"`
+	Storage    StorageConfig   `yaml:"storage"`
+	Timeouts   TimeoutConfig   `yaml:"timeouts"`
+	CORS       CORSConfig      `yaml:"cors"`
+	Lint       LintConfig      `yaml:"lint"`
+	Guardrails GuardrailConfig `yaml:"guardrails"`
+	Auth       AuthConfig      `yaml:"auth"`
+	Schema     SchemaConfig    `yaml:"schema"`
+	Sessions   SessionConfig   `yaml:"sessions"`
+	Cursors    CursorConfig    `yaml:"cursors"`
+	Imports    ImportConfig    `yaml:"imports"`
+	Features   FeatureToggles  `yaml:"features"`
+}
+
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+// Enabled indica si el servidor debe escuchar con TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Backends de ejecución admitidos.
+const (
+	// BackendMongo ejecuta los comandos en el servidor de mongo.uri.
+	BackendMongo = "mongo"
+	// BackendMemory los ejecuta en memoria del proceso, sin servidor; los
+	// datos se pierden al reiniciar.
+	BackendMemory = "memory"
+	// BackendFile los ejecuta en memoria y guarda cada escritura en
+	// storage.path, de modo que los datos sobreviven a un reinicio.
+	BackendFile = "file"
+)
+
+type MongoConfig struct {
+	URI Secret `yaml:"uri"`
+}
+
+type StorageConfig struct {
+	// Path es el archivo de datos del backend file.
+	Path string `yaml:"path"`
+}
+
+type TimeoutConfig struct {
+	// Mongo limita cada operación contra el servidor de MongoDB.
+	Mongo time.Duration `yaml:"mongo"`
+	// Analysis es el tiempo máximo de una petición a /analyze; timeoutMs
+	// solo puede reducirlo.
+	Analysis time.Duration `yaml:"analysis"`
+	Read     time.Duration `yaml:"read"`
+	Write    time.Duration `yaml:"write"`
+	Idle     time.Duration `yaml:"idle"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins son los orígenes (https://app.example.com) que pueden
+	// llamar a la API desde el navegador. "*" admite cualquiera; vacío, solo
+	// el mismo origen.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+type LintConfig struct {
+	// File es un archivo JSON { "rules": { ... } } como el de -lint-config.
+	File string `yaml:"file"`
+	// Rules cambia el nivel de las reglas; tiene prioridad sobre File.
+	Rules map[string]string `yaml:"rules"`
+}
+
+type GuardrailConfig struct {
+	ProtectedDatabases   []string `yaml:"protected_databases"`
+	ProtectedCollections []string `yaml:"protected_collections"`
+}
+
+type AuthConfig struct {
+	// APIKeysFile es el archivo de API keys y roles; vacío desactiva la
+	// autenticación.
+	APIKeysFile string `yaml:"api_keys_file"`
+}
+
+type SchemaConfig struct {
+	SampleSize int           `yaml:"sample_size"`
+	TTL        time.Duration `yaml:"ttl"`
+}
+
+type SessionConfig struct {
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+}
+
+type CursorConfig struct {
+	// BatchSize es el número de documentos de cada lote de find.
+	BatchSize int `yaml:"batch_size"`
+	// MaxResults es el máximo de documentos que devuelve un find entre
+	// todos sus lotes.
+	MaxResults  int           `yaml:"max_results"`
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+}
+
+type ImportConfig struct {
+	// BatchSize es el número de documentos que se escriben de una vez.
+	BatchSize int `yaml:"batch_size"`
+	// MaxBytes es el tamaño máximo de un archivo importado.
+	MaxBytes int `yaml:"max_bytes"`
+}
+
+// FeatureToggles activan o desactivan partes de la API.
+type FeatureToggles struct {
+	Trace           bool `yaml:"trace"`
+	Derivation      bool `yaml:"derivation"`
+	DryRun          bool `yaml:"dry_run"`
+	Tree            bool `yaml:"tree"`
+	Grammar         bool `yaml:"grammar"`
+	JSONSchema      bool `yaml:"jsonschema"`
+	SchemaInference bool `yaml:"schema_inference"`
+	Export          bool `yaml:"export"`
+	Import          bool `yaml:"import"`
+}
+
+// Default devuelve la configuración por defecto. No incluye la URI de
+// MongoDB, que es obligatoria con el backend mongo.
+func Default() *Config {
+	return &Config{
+		Listen:  ":8080",
+		Backend: BackendMongo,
+		Timeouts: TimeoutConfig{
+			Mongo:    10 * time.Second,
+			Analysis: 30 * time.Second,
+			Read:     15 * time.Second,
+			Write:    60 * time.Second,
+			Idle:     120 * time.Second,
+		},
+		Guardrails: GuardrailConfig{
+			ProtectedDatabases: append([]string(nil), policy.DefaultProtectedDatabases...),
+		},
+		Schema: SchemaConfig{
+			SampleSize: validator.DefaultSampleSize,
+			TTL:        validator.DefaultSchemaTTL,
+		},
+		Sessions: SessionConfig{IdleTimeout: session.DefaultIdleTimeout},
+		Cursors: CursorConfig{
+			BatchSize:   cursor.DefaultBatchSize,
+			MaxResults:  cursor.DefaultMaxResults,
+			IdleTimeout: cursor.DefaultIdleTimeout,
+		},
+		Imports: ImportConfig{
+			BatchSize: importer.DefaultBatchSize,
+			MaxBytes:  importer.DefaultMaxBytes,
+		},
+		Features: FeatureToggles{
+			Trace:           true,
+			Derivation:      true,
+			DryRun:          true,
+			Tree:            true,
+			Grammar:         true,
+			JSONSchema:      true,
+			SchemaInference: true,
+			Export:          true,
+			Import:          true,
+		},
+	}
+}
+
+// Load construye la configuración a partir de args (sin el nombre del
+// programa) y del entorno, y la valida.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	fs := flag.NewFlagSet("mongo-analyzer", flag.ContinueOnError)
+	path := fs.String("config", "", "archivo de configuración YAML (también ANALYZER_CONFIG)")
+
+	// Las opciones se aplican después del archivo y del entorno, de modo
+	// que tengan prioridad sobre ellos.
+	type assignment struct {
+		setting setting
+		value   string
+	}
+	var assignments []assignment
+	for _, s := range settings {
+		s := s
+		if s.flag == "" {
+			continue
+		}
+		fs.Func(s.flag, s.usage+" ("+s.env+")", func(value string) error {
+			assignments = append(assignments, assignment{s, value})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	if *path == "" {
+		*path, _ = lookupEnv("ANALYZER_CONFIG")
+	}
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok {
+			if err := s.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("%s: %v", s.env, err)
+			}
+		}
+	}
+
+	for _, a := range assignments {
+		if err := a.setting.set(cfg, a.value); err != nil {
+			return nil, fmt.Errorf("-%s: %v", a.setting.flag, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("no se pudo leer el archivo de configuración: %v", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("archivo de configuración %s inválido: %v", path, err)
+	}
+	return nil
+}
+
+// Validate comprueba la configuración y devuelve todos los problemas
+// encontrados a la vez.
+func (c *Config) Validate() error {
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	switch c.Backend {
+	case BackendMongo:
+		if c.Mongo.URI == "" {
+			fail("mongo.uri es obligatorio con el backend mongo (archivo de configuración o ANALYZER_MONGO_URI)")
+		}
+	case BackendMemory:
+	case BackendFile:
+		if c.Storage.Path == "" {
+			fail("storage.path es obligatorio con el backend file")
+		}
+	default:
+		fail("backend %q no es válido (%s, %s o %s)", c.Backend, BackendMongo, BackendMemory, BackendFile)
+	}
+	if c.Mongo.URI != "" {
+		if uri, err := url.Parse(c.Mongo.URI.Reveal()); err != nil || (uri.Scheme != "mongodb" && uri.Scheme != "mongodb+srv") {
+			fail("mongo.uri debe empezar por mongodb:// o mongodb+srv://")
+		}
+	}
+
+	if _, _, err := net.SplitHostPort(c.Listen); err != nil {
+		fail("listen %q no es una dirección válida (por ejemplo :8080 o 127.0.0.1:8080)", c.Listen)
+	}
+
+	if c.TLS.Enabled() {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			fail("tls necesita cert_file y key_file")
+		}
+		for _, file := range []string{c.TLS.CertFile, c.TLS.KeyFile} {
+			if _, err := os.Stat(file); file != "" && err != nil {
+				fail("tls: %v", err)
+			}
+		}
+	}
+
+	for _, duration := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"timeouts.mongo", c.Timeouts.Mongo},
+		{"timeouts.analysis", c.Timeouts.Analysis},
+		{"timeouts.read", c.Timeouts.Read},
+		{"timeouts.write", c.Timeouts.Write},
+		{"timeouts.idle", c.Timeouts.Idle},
+		{"schema.ttl", c.Schema.TTL},
+		{"sessions.idle_timeout", c.Sessions.IdleTimeout},
+		{"cursors.idle_timeout", c.Cursors.IdleTimeout},
+	} {
+		if duration.value <= 0 {
+			fail("%s debe ser mayor que cero", duration.name)
+		}
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || strings.Trim(parsed.Path, "/") != "" {
+			fail("cors.allowed_origins: %q no es un origen válido (https://host[:puerto])", origin)
+		}
+	}
+
+	if _, err := c.LintRules(); err != nil {
+		fail("lint: %v", err)
+	}
+
+	if c.Auth.APIKeysFile != "" {
+		if _, err := os.Stat(c.Auth.APIKeysFile); err != nil {
+			fail("auth.api_keys_file: %v", err)
+		}
+	}
+
+	if c.Schema.SampleSize <= 0 {
+		fail("schema.sample_size debe ser mayor que cero")
+	}
+
+	if c.Cursors.BatchSize <= 0 || c.Cursors.MaxResults <= 0 {
+		fail("cursors.batch_size y cursors.max_results deben ser mayores que cero")
+	} else if c.Cursors.BatchSize > c.Cursors.MaxResults {
+		fail("cursors.batch_size no puede ser mayor que cursors.max_results")
+	}
+
+	if c.Imports.BatchSize <= 0 || c.Imports.MaxBytes <= 0 {
+		fail("imports.batch_size e imports.max_bytes deben ser mayores que cero")
+	}
+
+	return errors.Join(problems...)
+}
+
+// LintRules devuelve el nivel de las reglas de lint: el del archivo y, por
+// encima, el de lint.rules.
+func (c *Config) LintRules() (entities.LintConfig, error) {
+	var rules entities.LintConfig
+	if c.Lint.File != "" {
+		var err error
+		if rules, err = validator.LoadLintConfig(c.Lint.File); err != nil {
+			return nil, err
+		}
+	}
+
+	overrides, err := validator.ParseLintConfig(c.Lint.Rules)
+	if err != nil {
+		return nil, err
+	}
+	return rules.Merge(overrides), nil
+}
+
+// AllowsOrigin indica si el navegador puede llamar a la API desde origin.
+func (c CORSConfig) AllowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
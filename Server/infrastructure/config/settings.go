@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Secret es un valor que no debe aparecer en los registros: al formatearlo
+// con %v o %s, o al serializarlo, se muestra redactado.
+type Secret string
+
+const redacted = "[redactado]"
+
+// Reveal devuelve el valor real. Solo debe usarse para pasarlo a quien lo
+// necesita, nunca para mostrarlo.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// RedactedURI devuelve la URI sin usuario, contraseña ni parámetros, apta
+// para los registros (mongodb+srv://cluster.example.net/base).
+func (c MongoConfig) RedactedURI() string {
+	uri, err := url.Parse(c.URI.Reveal())
+	if err != nil {
+		return redacted
+	}
+	uri.User = nil
+	uri.RawQuery = ""
+	return uri.String()
+}
+
+// setting es un valor que puede cambiarse con una variable de entorno y,
+// si flag no está vacío, con una opción de la línea de comandos.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+// settings enumera las variables de entorno y opciones admitidas. La URI
+// de MongoDB no tiene opción para que no quede a la vista en la lista de
+// procesos.
+var settings = []setting{
+	{"ANALYZER_LISTEN", "listen", "dirección en la que escucha el servidor", setString(func(c *Config) *string { return &c.Listen })},
+	{"ANALYZER_BACKEND", "backend", "dónde se ejecutan los comandos: mongo, memory o file", setString(func(c *Config) *string { return &c.Backend })},
+	{"ANALYZER_STORAGE_PATH", "storage-path", "archivo de datos del backend file", setString(func(c *Config) *string { return &c.Storage.Path })},
+	{"ANALYZER_TLS_CERT_FILE", "tls-cert", "certificado TLS", setString(func(c *Config) *string { return &c.TLS.CertFile })},
+	{"ANALYZER_TLS_KEY_FILE", "tls-key", "clave privada TLS", setString(func(c *Config) *string { return &c.TLS.KeyFile })},
+	{"ANALYZER_MONGO_URI", "", "", func(c *Config, value string) error { c.Mongo.URI = Secret(value); return nil }},
+	{"ANALYZER_MONGO_TIMEOUT", "mongo-timeout", "tiempo máximo de cada operación en MongoDB", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Mongo })},
+	{"ANALYZER_ANALYSIS_TIMEOUT", "analysis-timeout", "tiempo máximo de una petición a /analyze", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Analysis })},
+	{"ANALYZER_READ_TIMEOUT", "read-timeout", "tiempo máximo para leer una petición HTTP", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Read })},
+	{"ANALYZER_WRITE_TIMEOUT", "write-timeout", "tiempo máximo para escribir una respuesta HTTP", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Write })},
+	{"ANALYZER_IDLE_TIMEOUT", "idle-timeout", "tiempo que se mantiene abierta una conexión HTTP inactiva", setDuration(func(c *Config) *time.Duration { return &c.Timeouts.Idle })},
+	{"ANALYZER_ALLOWED_ORIGINS", "allowed-origins", "orígenes CORS permitidos, separados por comas", setList(func(c *Config) *[]string { return &c.CORS.AllowedOrigins })},
+	{"ANALYZER_LINT_CONFIG", "lint-config", "archivo JSON con el nivel de las reglas de lint", setString(func(c *Config) *string { return &c.Lint.File })},
+	{"ANALYZER_PROTECTED_DATABASES", "protected-databases", "bases de datos que nunca se pueden eliminar (admite comodines)", setList(func(c *Config) *[]string { return &c.Guardrails.ProtectedDatabases })},
+	{"ANALYZER_PROTECTED_COLLECTIONS", "protected-collections", "colecciones que nunca se pueden eliminar: coleccion o base.coleccion (admite comodines)", setList(func(c *Config) *[]string { return &c.Guardrails.ProtectedCollections })},
+	{"ANALYZER_API_KEYS", "api-keys", "archivo JSON con las API keys y sus roles; sin él no se exige clave", setString(func(c *Config) *string { return &c.Auth.APIKeysFile })},
+	{"ANALYZER_SCHEMA_SAMPLE_SIZE", "schema-sample-size", "documentos que se muestrean para inferir el esquema de una colección", setInt(func(c *Config) *int { return &c.Schema.SampleSize })},
+	{"ANALYZER_SCHEMA_TTL", "schema-ttl", "tiempo durante el que se reutiliza un esquema inferido", setDuration(func(c *Config) *time.Duration { return &c.Schema.TTL })},
+	{"ANALYZER_SESSION_IDLE_TIMEOUT", "session-idle-timeout", "tiempo sin uso tras el que caduca una sesión", setDuration(func(c *Config) *time.Duration { return &c.Sessions.IdleTimeout })},
+	{"ANALYZER_CURSOR_BATCH_SIZE", "cursor-batch-size", "documentos de cada lote de find", setInt(func(c *Config) *int { return &c.Cursors.BatchSize })},
+	{"ANALYZER_CURSOR_MAX_RESULTS", "cursor-max-results", "máximo de documentos de un find entre todos sus lotes", setInt(func(c *Config) *int { return &c.Cursors.MaxResults })},
+	{"ANALYZER_CURSOR_IDLE_TIMEOUT", "cursor-idle-timeout", "tiempo sin pedir lotes tras el que caduca un cursor", setDuration(func(c *Config) *time.Duration { return &c.Cursors.IdleTimeout })},
+	{"ANALYZER_IMPORT_BATCH_SIZE", "import-batch-size", "documentos que se escriben de una vez al importar", setInt(func(c *Config) *int { return &c.Imports.BatchSize })},
+	{"ANALYZER_IMPORT_MAX_BYTES", "import-max-bytes", "tamaño máximo en bytes de un archivo importado", setInt(func(c *Config) *int { return &c.Imports.MaxBytes })},
+	{"ANALYZER_FEATURE_TRACE", "feature-trace", "permite pedir la traza del análisis", setBool(func(c *Config) *bool { return &c.Features.Trace })},
+	{"ANALYZER_FEATURE_DERIVATION", "feature-derivation", "permite pedir la derivación del parser", setBool(func(c *Config) *bool { return &c.Features.Derivation })},
+	{"ANALYZER_FEATURE_DRY_RUN", "feature-dry-run", "permite el modo dry-run", setBool(func(c *Config) *bool { return &c.Features.DryRun })},
+	{"ANALYZER_FEATURE_TREE", "feature-tree", "publica GET /analyze/tree", setBool(func(c *Config) *bool { return &c.Features.Tree })},
+	{"ANALYZER_FEATURE_GRAMMAR", "feature-grammar", "publica GET /grammar", setBool(func(c *Config) *bool { return &c.Features.Grammar })},
+	{"ANALYZER_FEATURE_JSONSCHEMA", "feature-jsonschema", "publica /jsonschema/{db}/{collection}", setBool(func(c *Config) *bool { return &c.Features.JSONSchema })},
+	{"ANALYZER_FEATURE_SCHEMA_INFERENCE", "feature-schema-inference", "infiere esquemas de muestras y publica GET /schema/{db}/{collection}", setBool(func(c *Config) *bool { return &c.Features.SchemaInference })},
+	{"ANALYZER_FEATURE_EXPORT", "feature-export", "publica GET /export", setBool(func(c *Config) *bool { return &c.Features.Export })},
+	{"ANALYZER_FEATURE_IMPORT", "feature-import", "publica POST /import/{db}/{collection}", setBool(func(c *Config) *bool { return &c.Features.Import })},
+}
+
+func setString(field func(c *Config) *string) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func setList(field func(c *Config) *[]string) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return nil
+	}
+}
+
+func setDuration(field func(c *Config) *time.Duration) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q no es una duración válida (por ejemplo 30s o 5m)", value)
+		}
+		*field(c) = duration
+		return nil
+	}
+}
+
+func setInt(field func(c *Config) *int) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q no es un número entero", value)
+		}
+		*field(c) = number
+		return nil
+	}
+}
+
+func setBool(field func(c *Config) *bool) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q no es un booleano (true o false)", value)
+		}
+		*field(c) = enabled
+		return nil
+	}
+}
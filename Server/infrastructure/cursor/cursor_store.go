@@ -0,0 +1,334 @@
+package cursor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+)
+
+const (
+	// DefaultBatchSize es el número de documentos de cada lote.
+	DefaultBatchSize = 100
+	// DefaultMaxResults es el máximo de documentos que devuelve un cursor
+	// entre todos sus lotes.
+	DefaultMaxResults = 10000
+	// DefaultIdleTimeout es el tiempo sin pedir lotes tras el que caduca
+	// un cursor.
+	DefaultIdleTimeout = 10 * time.Minute
+)
+
+// Store guarda los cursores abiertos. Cada cursor pertenece a la sesión
+// que lo abrió y solo ella puede pedir sus lotes. Los cursores caducados se
+// cierran al acceder al almacén, de modo que no hace falta una goroutine
+// aparte.
+type Store struct {
+	batchSize   int
+	maxResults  int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	// mu protege cursors y la caducidad de cada cursor.
+	mu      sync.Mutex
+	cursors map[string]*openCursor
+}
+
+type openCursor struct {
+	owner      string
+	database   string
+	collection string
+	expiresAt  time.Time
+
+	// mu serializa las lecturas del iterador.
+	mu       sync.Mutex
+	iterator interfaces.DocumentIterator
+	returned int
+	closed   bool
+}
+
+func NewStore(batchSize, maxResults int, idleTimeout time.Duration) *Store {
+	return &Store{
+		batchSize:   batchSize,
+		maxResults:  maxResults,
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		cursors:     make(map[string]*openCursor),
+	}
+}
+
+// Open lee el primer lote de iterator y, si quedan documentos, guarda el
+// cursor para la sesión de ctx.
+func (s *Store) Open(ctx context.Context, iterator interfaces.DocumentIterator, database, collection string) (*entities.CursorPage, error) {
+	c := &openCursor{
+		owner:      ownerOf(ctx),
+		database:   database,
+		collection: collection,
+		iterator:   iterator,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page, err := s.read(ctx, c)
+	if err != nil || !page.HasMore {
+		return page, err
+	}
+
+	id, err := newCursorID()
+	if err != nil {
+		c.close(ctx)
+		return nil, err
+	}
+	page.ID = id
+
+	s.mu.Lock()
+	expired := s.purgeExpired()
+	c.expiresAt = s.now().Add(s.idleTimeout)
+	page.ExpiresAt = c.expiresAt
+	s.cursors[id] = c
+	s.mu.Unlock()
+
+	closeAll(expired)
+	return page, nil
+}
+
+// Next devuelve el siguiente lote del cursor id. Al devolver el último, el
+// cursor se cierra.
+func (s *Store) Next(ctx context.Context, id string) (*entities.CursorPage, error) {
+	c, err := s.acquire(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, entities.NewDiagnostic(i18n.CursorNotFound, id)
+	}
+
+	page, err := s.read(ctx, c)
+	if err != nil || !page.HasMore {
+		s.remove(id)
+		return page, err
+	}
+
+	page.ID = id
+	s.mu.Lock()
+	c.expiresAt = s.now().Add(s.idleTimeout)
+	page.ExpiresAt = c.expiresAt
+	s.mu.Unlock()
+	return page, nil
+}
+
+// Close cierra el cursor id antes de agotarlo.
+func (s *Store) Close(ctx context.Context, id string) error {
+	c, err := s.acquire(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.remove(id)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.close(ctx)
+}
+
+// acquire devuelve el cursor id si existe, no ha caducado y pertenece a la
+// sesión de ctx. A quien no es su dueño se le responde igual que si no
+// existiera.
+func (s *Store) acquire(ctx context.Context, id string) (*openCursor, error) {
+	s.mu.Lock()
+	expired := s.purgeExpired()
+	c, ok := s.cursors[id]
+	if ok && c.owner == ownerOf(ctx) {
+		// Se prolonga ya para que no caduque mientras se lee el lote
+		c.expiresAt = s.now().Add(s.idleTimeout)
+	}
+	s.mu.Unlock()
+
+	closeAll(expired)
+	if !ok || c.owner != ownerOf(ctx) {
+		return nil, entities.NewDiagnostic(i18n.CursorNotFound, id)
+	}
+	return c, nil
+}
+
+func (s *Store) remove(id string) {
+	s.mu.Lock()
+	delete(s.cursors, id)
+	s.mu.Unlock()
+}
+
+// read lee el siguiente lote. Si el resultado se agota, o se alcanza el
+// máximo de documentos, cierra el iterador. Debe llamarse con c.mu
+// bloqueado.
+func (s *Store) read(ctx context.Context, c *openCursor) (*entities.CursorPage, error) {
+	size := s.batchSize
+	if remaining := s.maxResults - c.returned; size > remaining {
+		size = remaining
+	}
+
+	documents, err := c.iterator.Next(ctx, size)
+	if err != nil {
+		c.close(ctx)
+		return nil, err
+	}
+	c.returned += len(documents)
+
+	page := &entities.CursorPage{
+		Database:   c.database,
+		Collection: c.collection,
+		Documents:  documents,
+		Returned:   c.returned,
+		HasMore:    len(documents) == size,
+	}
+	if page.HasMore && c.returned >= s.maxResults {
+		// Se lee un documento más solo para saber si el límite ha dejado
+		// alguno fuera
+		extra, err := c.iterator.Next(ctx, 1)
+		page.Truncated = err == nil && len(extra) > 0
+		page.HasMore = false
+	}
+	if !page.HasMore {
+		c.close(ctx)
+	}
+	return page, nil
+}
+
+// close cierra el iterador una sola vez. Debe llamarse con c.mu bloqueado.
+func (c *openCursor) close(ctx context.Context) error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.iterator.Close(ctx)
+}
+
+// purgeExpired saca del almacén los cursores caducados y los devuelve para
+// cerrarlos fuera de s.mu. Debe llamarse con s.mu bloqueado.
+func (s *Store) purgeExpired() []*openCursor {
+	var expired []*openCursor
+	now := s.now()
+	for id, c := range s.cursors {
+		if now.After(c.expiresAt) {
+			expired = append(expired, c)
+			delete(s.cursors, id)
+		}
+	}
+	return expired
+}
+
+func closeAll(cursors []*openCursor) {
+	for _, c := range cursors {
+		c.mu.Lock()
+		c.close(context.Background())
+		c.mu.Unlock()
+	}
+}
+
+func ownerOf(ctx context.Context) string {
+	if session := entities.SessionFromContext(ctx); session != nil {
+		return session.ID
+	}
+	return ""
+}
+
+func newCursorID() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
+
+// FirstPage devuelve el primer lote de iterator. Con registry, el cursor
+// queda abierto si quedan documentos; sin él (registry nil) se devuelve el
+// resultado completo en un solo lote.
+func FirstPage(ctx context.Context, registry interfaces.CursorRegistry, iterator interfaces.DocumentIterator, database, collection string) (*entities.CursorPage, error) {
+	if registry != nil {
+		return registry.Open(ctx, iterator, database, collection)
+	}
+	defer iterator.Close(ctx)
+
+	documents, err := iterator.Next(ctx, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.CursorPage{
+		Database:   database,
+		Collection: collection,
+		Documents:  documents,
+		Returned:   len(documents),
+	}, nil
+}
+
+// SliceIterator recorre documentos que ya están en memoria.
+type SliceIterator struct {
+	documents []map[string]interface{}
+}
+
+func NewSliceIterator(documents []map[string]interface{}) *SliceIterator {
+	return &SliceIterator{documents: documents}
+}
+
+func (i *SliceIterator) Next(ctx context.Context, size int) ([]map[string]interface{}, error) {
+	if size > len(i.documents) {
+		size = len(i.documents)
+	}
+	batch := i.documents[:size]
+	i.documents = i.documents[size:]
+	return batch, nil
+}
+
+func (i *SliceIterator) Close(ctx context.Context) error {
+	i.documents = nil
+	return nil
+}
+
+// FindResult es el resultado de find a partir de uno de sus lotes, con la
+// misma forma en todos los executors y en /cursor/{id}/next.
+func FindResult(page *entities.CursorPage) map[string]interface{} {
+	documents := make([]bson.M, len(page.Documents))
+	for i, document := range page.Documents {
+		documents[i] = document
+	}
+
+	result := map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.ExecDocumentsFound, len(documents)),
+		"documents":  documents,
+		"count":      len(documents),
+		"collection": page.Collection,
+		"database":   page.Database,
+	}
+
+	first := page.Returned == len(documents)
+	switch {
+	case page.HasMore && first:
+		result["message"] = entities.NewDiagnostic(i18n.ExecDocumentsFirstBatch, len(documents), page.ID)
+	case page.HasMore:
+		result["message"] = entities.NewDiagnostic(i18n.ExecDocumentsNextBatch, len(documents), page.Returned, page.ID)
+	case page.Truncated:
+		result["message"] = entities.NewDiagnostic(i18n.ExecDocumentsTruncated, page.Returned)
+		result["truncated"] = true
+	case !first:
+		result["message"] = entities.NewDiagnostic(i18n.ExecDocumentsLastBatch, len(documents), page.Returned)
+	}
+
+	if page.HasMore {
+		result["cursorId"] = page.ID
+		result["hasMore"] = true
+		result["expiresAt"] = page.ExpiresAt
+	}
+	if page.HasMore || !first {
+		result["returned"] = page.Returned
+	}
+	return result
+}
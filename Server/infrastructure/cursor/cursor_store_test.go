@@ -0,0 +1,102 @@
+package cursor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// closingIterator anota si se ha cerrado.
+type closingIterator struct {
+	*SliceIterator
+	closed bool
+}
+
+func (i *closingIterator) Close(ctx context.Context) error {
+	i.closed = true
+	return i.SliceIterator.Close(ctx)
+}
+
+func numbered(n int) *closingIterator {
+	documents := make([]map[string]interface{}, n)
+	for i := range documents {
+		documents[i] = map[string]interface{}{"_id": i}
+	}
+	return &closingIterator{SliceIterator: NewSliceIterator(documents)}
+}
+
+func withSession(id string) context.Context {
+	return entities.ContextWithSession(context.Background(), entities.NewSession(id, time.Now()))
+}
+
+func TestStoreReturnsBatchesUntilExhausted(t *testing.T) {
+	store := NewStore(2, 100, time.Minute)
+	ctx := withSession("a")
+	iterator := numbered(5)
+
+	page, err := store.Open(ctx, iterator, "escuela", "alumnos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sizes []int
+	sizes = append(sizes, len(page.Documents))
+	for page.HasMore {
+		if page, err = store.Next(ctx, page.ID); err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(page.Documents))
+	}
+
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 || page.Returned != 5 {
+		t.Fatalf("lotes = %v, devueltos %d", sizes, page.Returned)
+	}
+	if !iterator.closed {
+		t.Fatal("el iterador no se cerró al agotarse")
+	}
+}
+
+func TestStoreTruncatesAtMaxResults(t *testing.T) {
+	store := NewStore(2, 3, time.Minute)
+	ctx := withSession("a")
+
+	page, _ := store.Open(ctx, numbered(10), "escuela", "alumnos")
+	page, err := store.Next(ctx, page.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Documents) != 1 || page.HasMore || !page.Truncated {
+		t.Fatalf("último lote = %+v", page)
+	}
+}
+
+func TestStoreHidesCursorsFromOtherSessions(t *testing.T) {
+	store := NewStore(1, 100, time.Minute)
+	page, _ := store.Open(withSession("a"), numbered(3), "escuela", "alumnos")
+
+	if _, err := store.Next(withSession("b"), page.ID); err == nil {
+		t.Fatal("otra sesión no debería poder leer el cursor")
+	}
+	if _, err := store.Next(withSession("a"), page.ID); err != nil {
+		t.Fatalf("la sesión dueña debería poder leer el cursor: %v", err)
+	}
+}
+
+func TestStoreClosesExpiredCursors(t *testing.T) {
+	store := NewStore(1, 100, time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := withSession("a")
+	iterator := numbered(3)
+
+	page, _ := store.Open(ctx, iterator, "escuela", "alumnos")
+	now = now.Add(2 * time.Minute)
+
+	if _, err := store.Next(ctx, page.ID); err == nil {
+		t.Fatal("un cursor caducado no debería devolver más lotes")
+	}
+	if !iterator.closed {
+		t.Fatal("el iterador de un cursor caducado no se cerró")
+	}
+}
@@ -0,0 +1,348 @@
+package memory
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// matches evalúa un filtro de consulta sobre un documento con la semántica
+// de MongoDB: las rutas con puntos atraviesan subdocumentos y arrays, y una
+// condición sobre un array se cumple si la cumple alguno de sus elementos.
+func matches(document map[string]interface{}, filter map[string]interface{}) (bool, error) {
+	for _, key := range sortedKeys(filter) {
+		value := filter[key]
+
+		var ok bool
+		var err error
+		switch key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(document, key, value)
+		case "$comment":
+			ok = true
+		case "$where", "$expr", "$text", "$jsonSchema":
+			return false, entities.NewDiagnostic(i18n.ExecUnsupportedOperator, key)
+		default:
+			ok, err = matchField(document, key, value)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchLogical(document map[string]interface{}, operator string, value interface{}) (bool, error) {
+	clauses, _ := value.([]interface{})
+	for _, clause := range clauses {
+		filter, _ := clause.(map[string]interface{})
+		ok, err := matches(document, filter)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case operator == "$and" && !ok:
+			return false, nil
+		case operator == "$or" && ok:
+			return true, nil
+		case operator == "$nor" && ok:
+			return false, nil
+		}
+	}
+	return operator != "$or", nil
+}
+
+// matchField evalúa la condición de un campo: un valor (igualdad) o un
+// documento de operadores.
+func matchField(document map[string]interface{}, path string, condition interface{}) (bool, error) {
+	values := lookup(document, path)
+
+	operators, ok := condition.(map[string]interface{})
+	if !ok || !hasOperatorKeys(operators) {
+		return matchEquality(values, condition), nil
+	}
+	return matchOperators(values, operators)
+}
+
+func matchOperators(values []interface{}, operators map[string]interface{}) (bool, error) {
+	for _, operator := range sortedKeys(operators) {
+		operand := operators[operator]
+
+		var ok bool
+		var err error
+		switch operator {
+		case "$eq":
+			ok = matchEquality(values, operand)
+		case "$ne":
+			ok = !matchEquality(values, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			ok = matchRange(values, operator, operand)
+		case "$in":
+			ok = matchIn(values, operand)
+		case "$nin":
+			ok = !matchIn(values, operand)
+		case "$exists":
+			ok = (len(values) > 0) == truthy(operand)
+		case "$type":
+			ok = matchType(values, operand)
+		case "$size":
+			ok = matchSize(values, operand)
+		case "$all":
+			ok = matchAll(values, operand)
+		case "$elemMatch":
+			ok, err = matchElem(values, operand)
+		case "$regex":
+			ok, err = matchRegex(values, operand, operators["$options"])
+		case "$options":
+			ok = true // se evalúa junto con $regex
+		case "$mod":
+			ok = matchMod(values, operand)
+		case "$not":
+			if inner, isDocument := operand.(map[string]interface{}); isDocument {
+				ok, err = matchOperators(values, inner)
+				ok = !ok
+			}
+		default:
+			return false, entities.NewDiagnostic(i18n.ExecUnsupportedOperator, operator)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// lookup devuelve los valores que alcanza la ruta. Un segmento numérico
+// indexa un array; si no, la ruta continúa en cada elemento que sea un
+// documento.
+func lookup(value interface{}, path string) []interface{} {
+	var found []interface{}
+	var walk func(value interface{}, segments []string)
+	walk = func(value interface{}, segments []string) {
+		if len(segments) == 0 {
+			found = append(found, value)
+			return
+		}
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			if child, ok := typed[segments[0]]; ok {
+				walk(child, segments[1:])
+			}
+		case []interface{}:
+			if index, err := strconv.Atoi(segments[0]); err == nil {
+				if index >= 0 && index < len(typed) {
+					walk(typed[index], segments[1:])
+				}
+				return
+			}
+			for _, element := range typed {
+				if _, ok := element.(map[string]interface{}); ok {
+					walk(element, segments)
+				}
+			}
+		}
+	}
+	walk(value, strings.Split(path, "."))
+	return found
+}
+
+// candidates añade a los valores los elementos de los que son arrays.
+func candidates(values []interface{}) []interface{} {
+	all := append([]interface{}(nil), values...)
+	for _, value := range values {
+		if array, ok := value.([]interface{}); ok {
+			all = append(all, array...)
+		}
+	}
+	return all
+}
+
+func matchEquality(values []interface{}, expected interface{}) bool {
+	if expected == nil && len(values) == 0 {
+		return true // { campo: null } también coincide si el campo no existe
+	}
+	for _, candidate := range candidates(values) {
+		if equal(candidate, expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRange(values []interface{}, operator string, operand interface{}) bool {
+	for _, candidate := range candidates(values) {
+		if typeOrder(candidate) != typeOrder(operand) {
+			continue // las comparaciones solo se hacen dentro del mismo tipo
+		}
+		c := compare(candidate, operand)
+		switch {
+		case operator == "$gt" && c > 0,
+			operator == "$gte" && c >= 0,
+			operator == "$lt" && c < 0,
+			operator == "$lte" && c <= 0:
+			return true
+		}
+	}
+	return false
+}
+
+func matchIn(values []interface{}, operand interface{}) bool {
+	options, _ := operand.([]interface{})
+	for _, option := range options {
+		if matchEquality(values, option) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchType(values []interface{}, operand interface{}) bool {
+	wanted := map[string]bool{}
+	var add func(interface{})
+	add = func(value interface{}) {
+		switch typed := value.(type) {
+		case string:
+			if typed == "number" {
+				for _, alias := range []string{"int", "long", "double", "decimal"} {
+					wanted[alias] = true
+				}
+			}
+			wanted[typed] = true
+		case []interface{}:
+			for _, element := range typed {
+				add(element)
+			}
+		default:
+			if isNumber(value) {
+				wanted[bsonTypeAliases[int(toFloat(value))]] = true
+			}
+		}
+	}
+	add(operand)
+
+	for _, candidate := range candidates(values) {
+		if wanted[typeName(candidate)] {
+			return true
+		}
+	}
+	return false
+}
+
+// bsonTypeAliases traduce los números de tipo de $type a su alias.
+var bsonTypeAliases = map[int]string{
+	1: "double", 2: "string", 3: "object", 4: "array", 5: "binData", 7: "objectId",
+	8: "bool", 9: "date", 10: "null", 11: "regex", 16: "int", 17: "timestamp", 18: "long", 19: "decimal",
+}
+
+func matchSize(values []interface{}, operand interface{}) bool {
+	for _, value := range values {
+		if array, ok := value.([]interface{}); ok && isNumber(operand) && float64(len(array)) == toFloat(operand) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAll(values []interface{}, operand interface{}) bool {
+	required, _ := operand.([]interface{})
+	if len(required) == 0 {
+		return false
+	}
+	for _, value := range required {
+		if !matchEquality(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchElem(values []interface{}, operand interface{}) (bool, error) {
+	condition, _ := operand.(map[string]interface{})
+	for _, value := range values {
+		array, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, element := range array {
+			var ok bool
+			var err error
+			if hasOperatorKeys(condition) && !isLogicalFilter(condition) {
+				ok, err = matchOperators([]interface{}{element}, condition)
+			} else if document, isDocument := element.(map[string]interface{}); isDocument {
+				ok, err = matches(document, condition)
+			}
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func isLogicalFilter(condition map[string]interface{}) bool {
+	for key := range condition {
+		if key == "$and" || key == "$or" || key == "$nor" {
+			return true
+		}
+	}
+	return false
+}
+
+func matchRegex(values []interface{}, pattern, options interface{}) (bool, error) {
+	expression, _ := pattern.(string)
+	if flags, _ := options.(string); flags != "" {
+		var supported strings.Builder
+		for _, flag := range flags {
+			if strings.ContainsRune("ims", flag) {
+				supported.WriteRune(flag)
+			}
+		}
+		if supported.Len() > 0 {
+			expression = "(?" + supported.String() + ")" + expression
+		}
+	}
+
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return false, err
+	}
+	for _, candidate := range candidates(values) {
+		if text, ok := candidate.(string); ok && compiled.MatchString(text) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func matchMod(values []interface{}, operand interface{}) bool {
+	pair, _ := operand.([]interface{})
+	if len(pair) != 2 || !isNumber(pair[0]) || !isNumber(pair[1]) || toFloat(pair[0]) == 0 {
+		return false
+	}
+	divisor, remainder := int64(toFloat(pair[0])), int64(toFloat(pair[1]))
+	for _, candidate := range candidates(values) {
+		if isNumber(candidate) && int64(toFloat(candidate))%divisor == remainder {
+			return true
+		}
+	}
+	return false
+}
+
+func truthy(value interface{}) bool {
+	switch typed := value.(type) {
+	case bool:
+		return typed
+	case nil:
+		return false
+	}
+	if isNumber(value) {
+		return toFloat(value) != 0
+	}
+	return true
+}
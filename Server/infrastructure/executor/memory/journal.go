@@ -0,0 +1,432 @@
+package memory
+
+// Formato del archivo de datos del backend file
+//
+// El archivo es un registro de escrituras que solo crece por el final:
+//
+//	cabecera   8 bytes   "MAJRNL01"
+//	registro   4 bytes   longitud del contenido, uint32 little-endian
+//	           4 bytes   CRC-32C (Castagnoli) del contenido, uint32 little-endian
+//	           n bytes   contenido: un documento BSON
+//	registro   ...
+//
+// El contenido de cada registro es una escritura:
+//
+//	{ op: "insert", db: "escuela", coll: "alumnos", id: <_id>, doc: { ... } }
+//
+// op es createDatabase, createCollection, insert, replace, delete,
+// dropCollection o dropDatabase; id es el _id del documento en replace y
+// delete (null en las demás), y doc solo aparece en insert y replace. Al abrir el archivo se aplican los registros en orden.
+//
+// Cada escritura se añade y se sincroniza con fsync antes de aplicarse en
+// memoria, así que una escritura confirmada al cliente sobrevive a una caída.
+// Si el proceso cae a mitad de un registro, el último queda incompleto o con
+// un CRC que no coincide; al abrir se descarta y se trunca el archivo. Un
+// registro dañado seguido de otros válidos no puede deberse a una caída y
+// hace que la apertura falle, para no perder datos en silencio.
+//
+// Cuando el archivo acumula muchos registros que ya no hacen falta
+// (documentos reemplazados o eliminados), se compacta: se escribe una
+// instantánea del contenido actual (createDatabase y createCollection de cada
+// base y colección, e insert de cada documento en orden) en un archivo
+// temporal, se sincroniza y se renombra sobre el original. El renombrado es
+// atómico: tras una caída queda el archivo anterior o el compactado, nunca
+// una mezcla.
+//
+// El archivo no admite varios procesos a la vez: cada servidor debe usar el
+// suyo.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/interfaces"
+)
+
+const (
+	journalMagic = "MAJRNL01"
+	// recordHeaderSize es la longitud y el CRC que preceden a cada registro.
+	recordHeaderSize = 8
+	// maxRecordSize limita un registro al tamaño máximo de un documento de
+	// MongoDB más margen para los campos de la escritura.
+	maxRecordSize = 17 * 1024 * 1024
+	// compactMinRecords evita compactar archivos pequeños.
+	compactMinRecords = 1000
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// journal es el archivo de datos de un almacén.
+type journal struct {
+	path string
+	file *os.File
+	// records es el número de registros del archivo.
+	records int
+}
+
+// record es la forma en disco de una mutation.
+type record struct {
+	Op         string                 `bson:"op"`
+	Database   string                 `bson:"db,omitempty"`
+	Collection string                 `bson:"coll,omitempty"`
+	ID         interface{}            `bson:"id"`
+	Document   map[string]interface{} `bson:"doc,omitempty"`
+}
+
+// OpenFileExecutor abre (o crea) el archivo de datos de path, recupera su
+// contenido y devuelve un executor que guarda en él cada escritura.
+func OpenFileExecutor(path string, cursors interfaces.CursorRegistry) (*MemoryExecutor, error) {
+	executor := NewMemoryExecutor(cursors)
+	journal, err := openJournal(path, executor.store)
+	if err != nil {
+		return nil, err
+	}
+	executor.store.journal = journal
+	return executor, nil
+}
+
+// openJournal aplica al almacén los registros del archivo y lo deja abierto
+// para añadir más.
+func openJournal(path string, s *store) (*journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el archivo de datos: %v", err)
+	}
+
+	j := &journal{path: path, file: file}
+	if err := j.replay(s); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("archivo de datos %s: %v", path, err)
+	}
+	return j, nil
+}
+
+// replay lee el archivo desde el principio, aplica los registros válidos y
+// trunca un último registro incompleto.
+func (j *journal) replay(s *store) error {
+	info, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		if _, err := j.file.Write([]byte(journalMagic)); err != nil {
+			return err
+		}
+		return j.file.Sync()
+	}
+
+	reader := bufio.NewReader(j.file)
+	magic := make([]byte, len(journalMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != journalMagic {
+		return errors.New("no es un archivo de datos del analizador")
+	}
+
+	offset := int64(len(journalMagic))
+	for {
+		payload, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		var m mutation
+		if err == nil {
+			m, err = decodeMutation(payload)
+		}
+		if err != nil {
+			// Solo un registro dañado al final se debe a una caída
+			torn, checkErr := j.tornTail(offset, info.Size())
+			if checkErr != nil {
+				return checkErr
+			}
+			if !torn {
+				return fmt.Errorf("registro dañado en la posición %d: %v", offset, err)
+			}
+			if err := j.file.Truncate(offset); err != nil {
+				return err
+			}
+			if err := j.file.Sync(); err != nil {
+				return err
+			}
+			break
+		}
+
+		s.apply(m)
+		j.records++
+		offset += recordHeaderSize + int64(len(payload))
+	}
+
+	_, err = j.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// tornTail indica si lo que hay desde offset hasta el final del archivo es
+// un registro a medio escribir: uno cuya longitud llega hasta el final o
+// más allá, o bytes a cero que el sistema de archivos reservó sin llegar a
+// escribirlos.
+func (j *journal) tornTail(offset, size int64) (bool, error) {
+	rest := make([]byte, size-offset)
+	if _, err := j.file.ReadAt(rest, offset); err != nil && err != io.EOF {
+		return false, err
+	}
+	if len(rest) < recordHeaderSize {
+		return true, nil
+	}
+	if offset+recordHeaderSize+int64(binary.LittleEndian.Uint32(rest[:4])) >= size {
+		return true, nil
+	}
+	for _, b := range rest {
+		if b != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// readRecord lee un registro. Devuelve io.EOF si no quedan registros e
+// io.ErrUnexpectedEOF si el último está incompleto.
+func readRecord(reader io.Reader) ([]byte, error) {
+	header := make([]byte, recordHeaderSize)
+	if n, err := io.ReadFull(reader, header); err != nil {
+		if n == 0 {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	size := binary.LittleEndian.Uint32(header[:4])
+	if size > maxRecordSize {
+		return nil, fmt.Errorf("longitud %d fuera de rango", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(header[4:]) {
+		return payload, errors.New("el CRC no coincide")
+	}
+	return payload, nil
+}
+
+// append añade la escritura al final del archivo y espera a que llegue al
+// disco.
+func (j *journal) append(m mutation) error {
+	frame, err := encodeRecord(m)
+	if err != nil {
+		return err
+	}
+	offset, err := j.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.file.Write(frame)
+	if err == nil {
+		err = j.file.Sync()
+	}
+	if err != nil {
+		// Se descarta lo que se haya llegado a escribir para que el
+		// siguiente registro no quede detrás de uno incompleto
+		j.file.Truncate(offset)
+		j.file.Seek(offset, io.SeekStart)
+		return fmt.Errorf("no se pudo guardar la escritura: %v", err)
+	}
+
+	j.records++
+	return nil
+}
+
+// compactIfNeeded compacta el archivo cuando más de la mitad de sus
+// registros sobran. Se llama después de aplicar cada escritura, con el
+// almacén bloqueado.
+func (j *journal) compactIfNeeded(s *store) {
+	if j.records < compactMinRecords || j.records < 2*s.liveRecords() {
+		return
+	}
+	// Si la compactación falla, el archivo actual sigue siendo válido y se
+	// vuelve a intentar en la siguiente escritura.
+	_ = j.compact(s)
+}
+
+// compact reescribe el archivo con una instantánea del almacén.
+func (j *journal) compact(s *store) error {
+	temporary := j.path + ".compact"
+	file, err := os.OpenFile(temporary, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary)
+
+	writer := bufio.NewWriter(file)
+	records := 0
+	_, err = writer.WriteString(journalMagic)
+	if err == nil {
+		err = s.snapshot(func(m mutation) error {
+			frame, err := encodeRecord(m)
+			if err == nil {
+				_, err = writer.Write(frame)
+				records++
+			}
+			return err
+		})
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := os.Rename(temporary, j.path); err != nil {
+		file.Close()
+		return err
+	}
+	syncDirectory(j.path)
+
+	j.file.Close()
+	j.file = file
+	j.records = records
+	return nil
+}
+
+// syncDirectory sincroniza el directorio del archivo para que el renombrado
+// sobreviva a una caída. En los sistemas que no lo admiten no hace nada.
+func syncDirectory(path string) {
+	directory, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+	directory.Sync()
+	directory.Close()
+}
+
+func (j *journal) close() error {
+	return j.file.Close()
+}
+
+// snapshot llama a emit con las escrituras que reconstruyen el almacén.
+// Debe llamarse con mu bloqueado.
+func (s *store) snapshot(emit func(mutation) error) error {
+	for _, database := range s.databaseNames() {
+		if err := emit(mutation{op: opCreateDatabase, database: database}); err != nil {
+			return err
+		}
+		for _, name := range s.collectionNames(database) {
+			if err := emit(mutation{op: opCreateCollection, database: database, collection: name}); err != nil {
+				return err
+			}
+			for _, document := range s.databases[database][name].documents {
+				if err := emit(mutation{op: opInsert, database: database, collection: name, document: document}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// liveRecords es el número de registros de una instantánea del almacén.
+func (s *store) liveRecords() int {
+	count := 0
+	for _, collections := range s.databases {
+		count++
+		for _, target := range collections {
+			count += 1 + len(target.documents)
+		}
+	}
+	return count
+}
+
+func encodeRecord(m mutation) ([]byte, error) {
+	payload, err := bson.Marshal(record{
+		Op:         m.op,
+		Database:   m.database,
+		Collection: m.collection,
+		ID:         m.id,
+		Document:   m.document,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo codificar la escritura: %v", err)
+	}
+
+	frame := make([]byte, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[4:8], crc32.Checksum(payload, crcTable))
+	copy(frame[recordHeaderSize:], payload)
+	return frame, nil
+}
+
+func decodeMutation(payload []byte) (mutation, error) {
+	var decoded struct {
+		Op         string      `bson:"op"`
+		Database   string      `bson:"db"`
+		Collection string      `bson:"coll"`
+		ID         interface{} `bson:"id"`
+		Document   bson.M      `bson:"doc"`
+	}
+	if err := bson.Unmarshal(payload, &decoded); err != nil {
+		return mutation{}, err
+	}
+
+	switch decoded.Op {
+	case opCreateDatabase, opCreateCollection, opInsert, opReplace, opDelete, opDropCollection, opDropDatabase:
+	default:
+		return mutation{}, fmt.Errorf("operación desconocida %q", decoded.Op)
+	}
+
+	m := mutation{
+		op:         decoded.Op,
+		database:   decoded.Database,
+		collection: decoded.Collection,
+		id:         fromBSON(decoded.ID),
+	}
+	if decoded.Document != nil {
+		m.document, _ = fromBSON(decoded.Document).(map[string]interface{})
+	}
+	return m, nil
+}
+
+// fromBSON convierte los tipos que devuelve el driver a los que guarda el
+// almacén (los del parser, primitive.ObjectID y time.Time).
+func fromBSON(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case primitive.M:
+		document := make(map[string]interface{}, len(typed))
+		for key, element := range typed {
+			document[key] = fromBSON(element)
+		}
+		return document
+	case primitive.D:
+		document := make(map[string]interface{}, len(typed))
+		for _, element := range typed {
+			document[element.Key] = fromBSON(element.Value)
+		}
+		return document
+	case primitive.A:
+		array := make([]interface{}, len(typed))
+		for i, element := range typed {
+			array[i] = fromBSON(element)
+		}
+		return array
+	case int32:
+		return int(typed)
+	case int64:
+		return int(typed)
+	case primitive.DateTime:
+		return typed.Time().UTC()
+	case primitive.Null:
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"mongo-analyzer/domain/entities"
+)
+
+func TestFileExecutorRecoversAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyzer.db")
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+
+	executor, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := []*entities.MongoCommand{
+		{Type: entities.USE_DATABASE, Database: "escuela"},
+		{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 1, "nombre": "Ana", "notas": []interface{}{7, 9.5}}},
+		{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 2, "nombre": "Luis"}},
+		{Type: entities.UPDATE_ONE, Collection: "alumnos", Filter: map[string]interface{}{"_id": 2}, Update: map[string]interface{}{"$currentDate": map[string]interface{}{"visto": true}}},
+		{Type: entities.DELETE_ONE, Collection: "alumnos", Filter: map[string]interface{}{"_id": 1}},
+		{Type: entities.CREATE_COLLECTION, Collection: "cursos"},
+	}
+	for _, command := range commands {
+		if _, err := executor.Execute(ctx, command); err != nil {
+			t.Fatalf("%v: %v", command.Type, err)
+		}
+	}
+	before := documents(t, executor, ctx, "alumnos")
+	executor.Close()
+
+	// Una caída a mitad de un registro deja basura al final del archivo
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte{42, 0, 0, 0, 1, 2})
+	file.Close()
+
+	reopened, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+
+	after := documents(t, reopened, ctx, "alumnos")
+	if len(after) != 1 || !equal(map[string]interface{}(before[0]), map[string]interface{}(after[0])) {
+		t.Fatalf("tras reabrir = %v, se esperaba %v", after, before)
+	}
+	if collections, _ := reopened.ListCollections(ctx); len(collections) != 2 {
+		t.Fatalf("colecciones tras reabrir = %v", collections)
+	}
+
+	if _, err := reopened.Execute(ctx, &entities.MongoCommand{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 3}}); err != nil {
+		t.Fatalf("escribir tras truncar el registro incompleto: %v", err)
+	}
+}
+
+func TestFileExecutorRejectsCorruptionBeforeValidRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyzer.db")
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+
+	executor, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.USE_DATABASE, Database: "escuela"})
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 1}})
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 2}})
+	executor.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(journalMagic)+recordHeaderSize+2] ^= 0xff // contenido del primer registro
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenFileExecutor(path, nil); err == nil {
+		t.Fatal("un registro dañado en medio del archivo debería impedir abrirlo")
+	}
+}
+
+func TestJournalCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyzer.db")
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+
+	executor, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.USE_DATABASE, Database: "escuela"})
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.INSERT_ONE, Collection: "contador", Document: map[string]interface{}{"_id": 1, "n": 0}})
+	for i := 0; i < compactMinRecords; i++ {
+		executor.Execute(ctx, &entities.MongoCommand{Type: entities.UPDATE_ONE, Collection: "contador", Filter: map[string]interface{}{"_id": 1}, Update: map[string]interface{}{"$inc": map[string]interface{}{"n": 1}}})
+	}
+	if records := executor.store.journal.records; records >= compactMinRecords {
+		t.Fatalf("el archivo no se compactó: %d registros", records)
+	}
+	executor.Close()
+
+	reopened, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	found := documents(t, reopened, ctx, "contador")
+	if len(found) != 1 || found[0]["n"] != compactMinRecords {
+		t.Fatalf("tras compactar = %v", found)
+	}
+}
+
+func documents(t *testing.T, executor *MemoryExecutor, ctx context.Context, collection string) []bson.M {
+	t.Helper()
+
+	result, err := executor.Execute(ctx, &entities.MongoCommand{Type: entities.FIND, Collection: collection})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, _ := result.(map[string]interface{})["documents"].([]bson.M)
+	return found
+}
+
+// Como en MongoDB, use no crea la base de datos y eliminar una colección
+// que no existe no escribe nada.
+func TestDatabasesAreCreatedOnFirstWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyzer.db")
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+
+	executor, err := OpenFileExecutor(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer executor.Close()
+
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.USE_DATABASE, Database: "Escuela"})
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.DROP_COLLECTION, Collection: "alumnos"})
+	if databases, _ := executor.ListDatabases(ctx); len(databases) != 0 {
+		t.Fatalf("bases de datos sin escrituras = %v", databases)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(journalMagic)) {
+		t.Fatalf("el journal no debería tener registros: %v, %v", info.Size(), err)
+	}
+
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.INSERT_ONE, Collection: "alumnos", Document: map[string]interface{}{"_id": 1}})
+	if databases, _ := executor.ListDatabases(ctx); len(databases) != 1 || databases[0] != "Escuela" {
+		t.Fatalf("bases de datos tras insertar = %v", databases)
+	}
+
+	executor.Execute(ctx, &entities.MongoCommand{Type: entities.DROP_COLLECTION, Collection: "alumnos"})
+	if databases, _ := executor.ListDatabases(ctx); len(databases) != 0 {
+		t.Fatalf("bases de datos sin colecciones = %v", databases)
+	}
+}
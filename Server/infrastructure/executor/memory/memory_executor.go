@@ -0,0 +1,376 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+	"mongo-analyzer/infrastructure/cursor"
+)
+
+// MemoryExecutor implementa interfaces.MongoExecutor sin servidor: guarda
+// las bases de datos en memoria del proceso y evalúa filtros y operadores de
+// actualización con la semántica de MongoDB. Los resultados tienen la misma
+// forma que los de executor.MongoExecutor. Creado con OpenFileExecutor,
+// además guarda cada escritura en un archivo y la recupera al reiniciar.
+type MemoryExecutor struct {
+	store   *store
+	cursors interfaces.CursorRegistry
+	// now da la fecha de $currentDate; las pruebas pueden fijarla.
+	now func() time.Time
+}
+
+// NewMemoryExecutor crea un executor vacío. Con cursors, find devuelve el
+// primer lote y un cursor para los siguientes; sin él (nil), todos los
+// documentos.
+func NewMemoryExecutor(cursors interfaces.CursorRegistry) *MemoryExecutor {
+	return &MemoryExecutor{store: newStore(), cursors: cursors, now: time.Now}
+}
+
+// Connect no hace nada: no hay servidor al que conectarse.
+func (e *MemoryExecutor) Connect() error {
+	return nil
+}
+
+// Close cierra el archivo de datos, si lo hay.
+func (e *MemoryExecutor) Close() error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if e.store.journal == nil {
+		return nil
+	}
+	err := e.store.journal.close()
+	e.store.journal = nil
+	return err
+}
+
+func (e *MemoryExecutor) Execute(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch command.Type {
+	case entities.USE_DATABASE:
+		return e.executeUseDatabase(ctx, command)
+	case entities.CREATE_COLLECTION:
+		return e.executeCreateCollection(ctx, command)
+	case entities.INSERT_ONE:
+		return e.executeInsertOne(ctx, command)
+	case entities.FIND:
+		return e.executeFind(ctx, command)
+	case entities.UPDATE_ONE:
+		return e.executeUpdateOne(ctx, command)
+	case entities.REPLACE_ONE:
+		return e.executeReplaceOne(ctx, command)
+	case entities.DELETE_ONE:
+		return e.executeDeleteOne(ctx, command)
+	case entities.DROP_COLLECTION:
+		return e.executeDropCollection(ctx, command)
+	case entities.DROP_DATABASE:
+		return e.executeDropDatabase(ctx, command)
+	default:
+		return nil, entities.NewDiagnostic(i18n.ExecUnsupportedCommand)
+	}
+}
+
+// executeUseDatabase solo cambia la base de datos de la sesión: como en
+// MongoDB, la base de datos no existe hasta que se escribe en ella.
+func (e *MemoryExecutor) executeUseDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	entities.SessionFromContext(ctx).SetDatabase(command.Database)
+
+	return map[string]interface{}{
+		"message":  entities.NewDiagnostic(i18n.ExecDatabaseSwitched, command.Database),
+		"database": command.Database,
+	}, nil
+}
+
+func (e *MemoryExecutor) executeCreateCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if e.store.collection(database, command.Collection, false) != nil {
+		return nil, entities.NewDiagnostic(i18n.ExecCollectionExists, command.Collection)
+	}
+	if err := e.store.write(mutation{op: opCreateCollection, database: database, collection: command.Collection}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.ExecCollectionCreated, command.Collection),
+		"collection": command.Collection,
+		"database":   database,
+	}, nil
+}
+
+func (e *MemoryExecutor) executeInsertOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	document := cloneDocument(command.Document)
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	if _, ok := document["_id"]; !ok {
+		document["_id"] = primitive.NewObjectID()
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if target := e.store.collection(database, command.Collection, false); target != nil && target.indexOf(document["_id"]) >= 0 {
+		return nil, entities.NewDiagnostic(i18n.ExecDuplicateKey, document["_id"], command.Collection)
+	}
+	if err := e.store.write(mutation{op: opInsert, database: database, collection: command.Collection, document: document}); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.ExecDocumentInserted),
+		"insertedId": document["_id"],
+		"collection": command.Collection,
+		"database":   database,
+	}, nil
+}
+
+func (e *MemoryExecutor) executeFind(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	iterator, err := e.Stream(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := cursor.FirstPage(ctx, e.cursors, iterator, e.databaseOf(ctx, command), command.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return cursor.FindResult(page), nil
+}
+
+// Stream devuelve un iterador sobre los documentos que encuentra un FIND.
+// Los documentos se copian al abrirlo, de modo que las escrituras
+// posteriores no lo afectan.
+func (e *MemoryExecutor) Stream(ctx context.Context, command *entities.MongoCommand) (interfaces.DocumentIterator, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.RLock()
+	found, err := e.find(database, command.Collection, command.Filter, 0)
+	for i, document := range found {
+		found[i] = cloneDocument(document)
+	}
+	e.store.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return cursor.NewSliceIterator(found), nil
+}
+
+func (e *MemoryExecutor) executeUpdateOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	found, err := e.find(database, command.Collection, command.Filter, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return updateResult(i18n.ExecUpdateCompleted, 0, 0, command.Collection, database), nil
+	}
+
+	var updated map[string]interface{}
+	if command.UpdatePipeline != nil {
+		updated, err = applyPipeline(found[0], command.UpdatePipeline)
+	} else {
+		updated, err = applyUpdate(found[0], command.Update, e.now().UTC().Truncate(time.Millisecond))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var modified int64
+	if !equal(found[0], updated) {
+		if err := e.store.write(mutation{op: opReplace, database: database, collection: command.Collection, id: found[0]["_id"], document: updated}); err != nil {
+			return nil, err
+		}
+		modified = 1
+	}
+	return updateResult(i18n.ExecUpdateCompleted, 1, modified, command.Collection, database), nil
+}
+
+func (e *MemoryExecutor) executeReplaceOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	found, err := e.find(database, command.Collection, command.Filter, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return updateResult(i18n.ExecReplaceCompleted, 0, 0, command.Collection, database), nil
+	}
+
+	replacement := cloneDocument(command.Document)
+	if replacement == nil {
+		replacement = map[string]interface{}{}
+	}
+	if id, ok := replacement["_id"]; ok && !equal(id, found[0]["_id"]) {
+		return nil, entities.NewDiagnostic(i18n.ExecImmutableID)
+	}
+	replacement["_id"] = found[0]["_id"]
+
+	var modified int64
+	if !equal(found[0], replacement) {
+		if err := e.store.write(mutation{op: opReplace, database: database, collection: command.Collection, id: found[0]["_id"], document: replacement}); err != nil {
+			return nil, err
+		}
+		modified = 1
+	}
+	return updateResult(i18n.ExecReplaceCompleted, 1, modified, command.Collection, database), nil
+}
+
+func updateResult(code string, matched, modified int64, collection, database string) map[string]interface{} {
+	return map[string]interface{}{
+		"message":       entities.NewDiagnostic(code),
+		"matchedCount":  matched,
+		"modifiedCount": modified,
+		"collection":    collection,
+		"database":      database,
+	}
+}
+
+func (e *MemoryExecutor) executeDeleteOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	found, err := e.find(database, command.Collection, command.Filter, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted int64
+	if len(found) > 0 {
+		if err := e.store.write(mutation{op: opDelete, database: database, collection: command.Collection, id: found[0]["_id"]}); err != nil {
+			return nil, err
+		}
+		deleted = 1
+	}
+
+	return map[string]interface{}{
+		"message":      entities.NewDiagnostic(i18n.ExecDeleteCompleted),
+		"deletedCount": deleted,
+		"collection":   command.Collection,
+		"database":     database,
+	}, nil
+}
+
+func (e *MemoryExecutor) executeDropCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	// Una colección que no existe no deja registro en el journal
+	if e.store.collection(database, command.Collection, false) != nil {
+		if err := e.store.write(mutation{op: opDropCollection, database: database, collection: command.Collection}); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.ExecCollectionDropped, command.Collection),
+		"collection": command.Collection,
+		"database":   database,
+	}, nil
+}
+
+func (e *MemoryExecutor) executeDropDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	databaseName := e.databaseOf(ctx, command)
+	if databaseName == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabaseToDrop)
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if err := e.store.write(mutation{op: opDropDatabase, database: databaseName}); err != nil {
+		return nil, err
+	}
+
+	entities.SessionFromContext(ctx).ClearDatabase(databaseName)
+
+	return map[string]interface{}{
+		"message":  entities.NewDiagnostic(i18n.ExecDatabaseDropped, databaseName),
+		"database": databaseName,
+	}, nil
+}
+
+// find devuelve, en orden de inserción, los documentos que cumplen el
+// filtro; limit 0 significa sin límite. Los documentos son los del almacén:
+// quien los devuelva fuera del paquete debe copiarlos. Debe llamarse con mu
+// bloqueado.
+func (e *MemoryExecutor) find(database, collection string, filter map[string]interface{}, limit int) ([]map[string]interface{}, error) {
+	target := e.store.collection(database, collection, false)
+	if target == nil {
+		return nil, nil
+	}
+
+	var found []map[string]interface{}
+	for _, document := range target.documents {
+		ok, err := matches(document, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			found = append(found, document)
+			if limit > 0 && len(found) == limit {
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+// CurrentDatabase devuelve la base de datos actual de la sesión de ctx.
+func (e *MemoryExecutor) CurrentDatabase(ctx context.Context) string {
+	return entities.SessionFromContext(ctx).Database()
+}
+
+// databaseOf devuelve la base de datos del comando o, si no indica ninguna,
+// la actual de la sesión.
+func (e *MemoryExecutor) databaseOf(ctx context.Context, command *entities.MongoCommand) string {
+	if command.Database != "" {
+		return command.Database
+	}
+	return e.CurrentDatabase(ctx)
+}
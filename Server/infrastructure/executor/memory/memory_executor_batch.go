@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// WriteBatch escribe el lote con el bloqueo tomado una sola vez. Un _id
+// repetido o un reemplazo que cambia el _id solo falla en ese documento;
+// un error al guardar en el archivo detiene el lote.
+func (e *MemoryExecutor) WriteBatch(ctx context.Context, database, collection string, writes []entities.BatchWrite) ([]entities.WriteOutcome, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	outcomes := make([]entities.WriteOutcome, len(writes))
+	for i, write := range writes {
+		var existing map[string]interface{}
+		if write.Filter != nil {
+			found, err := e.find(database, collection, write.Filter, 1)
+			if err != nil {
+				outcomes[i].Err = err
+				continue
+			}
+			if len(found) > 0 {
+				existing = found[0]
+			}
+		}
+
+		document := cloneDocument(write.Document)
+		if document == nil {
+			document = map[string]interface{}{}
+		}
+
+		if existing != nil {
+			if id, ok := document["_id"]; ok && !equal(id, existing["_id"]) {
+				outcomes[i].Err = entities.NewDiagnostic(i18n.ExecImmutableID)
+				continue
+			}
+			document["_id"] = existing["_id"]
+			if err := e.store.write(mutation{op: opReplace, database: database, collection: collection, id: existing["_id"], document: document}); err != nil {
+				return nil, err
+			}
+			outcomes[i].Updated = true
+			continue
+		}
+
+		if _, ok := document["_id"]; !ok {
+			document["_id"] = primitive.NewObjectID()
+		}
+		if target := e.store.collection(database, collection, false); target != nil && target.indexOf(document["_id"]) >= 0 {
+			outcomes[i].Err = entities.NewDiagnostic(i18n.ExecDuplicateKey, document["_id"], collection)
+			continue
+		}
+		if err := e.store.write(mutation{op: opInsert, database: database, collection: collection, document: document}); err != nil {
+			return nil, err
+		}
+	}
+	return outcomes, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// fieldSampleSize es el número de documentos que se leen para descubrir
+// campos, el mismo que usa executor.MongoExecutor.
+const fieldSampleSize = 50
+
+func (e *MemoryExecutor) ListDatabases(ctx context.Context) ([]string, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	return e.store.databaseNames(), nil
+}
+
+// ListCollections devuelve las colecciones de la base de datos actual.
+func (e *MemoryExecutor) ListCollections(ctx context.Context) ([]string, error) {
+	database := e.CurrentDatabase(ctx)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	return e.store.collectionNames(database), nil
+}
+
+// ListFields devuelve los campos de primer nivel de los primeros
+// documentos de la colección.
+func (e *MemoryExecutor) ListFields(ctx context.Context, collection string) ([]string, error) {
+	database := e.CurrentDatabase(ctx)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, document := range e.firstDocuments(database, collection, fieldSampleSize) {
+		for _, key := range sortedKeys(document) {
+			if !seen[key] {
+				seen[key] = true
+				fields = append(fields, key)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// CollectionSchema devuelve nil: las colecciones en memoria no tienen
+// validador.
+func (e *MemoryExecutor) CollectionSchema(ctx context.Context, database, collection string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+// SampleDocuments devuelve los primeros size documentos en orden de
+// inserción. A diferencia de $sample no es aleatorio, de modo que el
+// esquema inferido es reproducible.
+func (e *MemoryExecutor) SampleDocuments(ctx context.Context, database, collection string, size int) ([]map[string]interface{}, error) {
+	return e.firstDocuments(database, collection, size), nil
+}
+
+func (e *MemoryExecutor) firstDocuments(database, collection string, size int) []map[string]interface{} {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	target := e.store.collection(database, collection, false)
+	if target == nil {
+		return nil
+	}
+
+	documents := target.documents
+	if size > 0 && len(documents) > size {
+		documents = documents[:size]
+	}
+	copies := make([]map[string]interface{}, len(documents))
+	for i, document := range documents {
+		copies[i] = cloneDocument(document)
+	}
+	return copies
+}
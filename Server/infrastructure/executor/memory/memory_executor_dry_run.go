@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// DryRun informa qué haría command sin modificar el almacén.
+func (e *MemoryExecutor) DryRun(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	switch command.Type {
+	case entities.USE_DATABASE:
+		_, exists := e.store.databases[command.Database]
+		return map[string]interface{}{
+			"message":  entities.NewDiagnostic(i18n.DryRunUseDatabase, command.Database),
+			"dryRun":   true,
+			"database": command.Database,
+			"exists":   exists,
+		}, nil
+	case entities.CREATE_COLLECTION:
+		return e.dryRunCreateCollection(ctx, command)
+	case entities.INSERT_ONE:
+		return e.dryRunInsertOne(ctx, command)
+	case entities.FIND:
+		return e.dryRunMatch(ctx, command, 0, i18n.DryRunFind)
+	case entities.UPDATE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunUpdate)
+	case entities.REPLACE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunReplace)
+	case entities.DELETE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunDelete)
+	case entities.DROP_COLLECTION:
+		return e.dryRunDropCollection(ctx, command)
+	case entities.DROP_DATABASE:
+		return e.dryRunDropDatabase(ctx, command)
+	default:
+		return nil, entities.NewDiagnostic(i18n.ExecUnsupportedCommand)
+	}
+}
+
+func (e *MemoryExecutor) dryRunCreateCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	return map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.DryRunCreateCollection, command.Collection),
+		"dryRun":     true,
+		"collection": command.Collection,
+		"database":   database,
+		"exists":     e.store.collection(database, command.Collection, false) != nil,
+	}, nil
+}
+
+func (e *MemoryExecutor) dryRunInsertOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	return map[string]interface{}{
+		"message":          entities.NewDiagnostic(i18n.DryRunInsert, command.Collection),
+		"dryRun":           true,
+		"insertedCount":    1,
+		"collectionExists": e.store.collection(database, command.Collection, false) != nil,
+		"collection":       command.Collection,
+		"database":         database,
+	}, nil
+}
+
+// dryRunMatch cuenta los documentos que coinciden con el filtro, con el
+// mismo límite que executor.MongoExecutor.
+func (e *MemoryExecutor) dryRunMatch(ctx context.Context, command *entities.MongoCommand, limit int, code string) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	found, err := e.find(database, command.Collection, command.Filter, limit)
+	if err != nil {
+		return nil, err
+	}
+	matched := int64(len(found))
+
+	return map[string]interface{}{
+		"message":      entities.NewDiagnostic(code, matched),
+		"dryRun":       true,
+		"matchedCount": matched,
+		"collection":   command.Collection,
+		"database":     database,
+	}, nil
+}
+
+func (e *MemoryExecutor) dryRunDropCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	var count int64
+	if target := e.store.collection(database, command.Collection, false); target != nil {
+		count = int64(len(target.documents))
+	}
+
+	return map[string]interface{}{
+		"message":       entities.NewDiagnostic(i18n.DryRunDropCollection, command.Collection, count),
+		"dryRun":        true,
+		"documentCount": count,
+		"collection":    command.Collection,
+		"database":      database,
+	}, nil
+}
+
+func (e *MemoryExecutor) dryRunDropDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	databaseName := e.databaseOf(ctx, command)
+	if databaseName == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabaseToDrop)
+	}
+
+	names := e.store.collectionNames(databaseName)
+	collections := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		collections = append(collections, map[string]interface{}{
+			"collection":    name,
+			"documentCount": int64(len(e.store.databases[databaseName][name].documents)),
+		})
+	}
+
+	return map[string]interface{}{
+		"message":     entities.NewDiagnostic(i18n.DryRunDropDatabase, databaseName, len(collections)),
+		"dryRun":      true,
+		"collections": collections,
+		"database":    databaseName,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatches(t *testing.T) {
+	document := map[string]interface{}{
+		"_id":    1,
+		"nombre": "Ana",
+		"edad":   20,
+		"notas":  []interface{}{7, 9.5},
+		"cursos": []interface{}{
+			map[string]interface{}{"codigo": "bd", "nota": 9},
+			map[string]interface{}{"codigo": "so", "nota": 5},
+		},
+		"direccion": map[string]interface{}{"ciudad": "Quito"},
+	}
+
+	cases := []struct {
+		filter map[string]interface{}
+		want   bool
+	}{
+		{map[string]interface{}{}, true},
+		{map[string]interface{}{"nombre": "Ana"}, true},
+		{map[string]interface{}{"edad": 20.0}, true},
+		{map[string]interface{}{"edad": "20"}, false},
+		{map[string]interface{}{"edad": map[string]interface{}{"$gt": 18, "$lt": 21}}, true},
+		{map[string]interface{}{"edad": map[string]interface{}{"$gt": "a"}}, false},
+		{map[string]interface{}{"notas": 9.5}, true},
+		{map[string]interface{}{"notas": map[string]interface{}{"$size": 2, "$all": []interface{}{7, 9.5}}}, true},
+		{map[string]interface{}{"direccion.ciudad": "Quito"}, true},
+		{map[string]interface{}{"cursos.codigo": "so"}, true},
+		{map[string]interface{}{"cursos.1.codigo": "bd"}, false},
+		{map[string]interface{}{"cursos": map[string]interface{}{"$elemMatch": map[string]interface{}{"codigo": "so", "nota": map[string]interface{}{"$gt": 6}}}}, false},
+		{map[string]interface{}{"telefono": nil}, true},
+		{map[string]interface{}{"telefono": map[string]interface{}{"$exists": true}}, false},
+		{map[string]interface{}{"nombre": map[string]interface{}{"$regex": "^a", "$options": "i"}}, true},
+		{map[string]interface{}{"edad": map[string]interface{}{"$type": "number", "$mod": []interface{}{5, 0}}}, true},
+		{map[string]interface{}{"edad": map[string]interface{}{"$not": map[string]interface{}{"$in": []interface{}{19, 20}}}}, false},
+		{map[string]interface{}{"$or": []interface{}{map[string]interface{}{"edad": 1}, map[string]interface{}{"nombre": "Ana"}}}, true},
+		{map[string]interface{}{"$nor": []interface{}{map[string]interface{}{"nombre": "Ana"}}}, false},
+	}
+
+	for _, c := range cases {
+		got, err := matches(document, c.filter)
+		if err != nil {
+			t.Fatalf("%v: %v", c.filter, err)
+		}
+		if got != c.want {
+			t.Errorf("%v = %v, se esperaba %v", c.filter, got, c.want)
+		}
+	}
+
+	if _, err := matches(document, map[string]interface{}{"$where": "true"}); err == nil {
+		t.Error("$where debería rechazarse")
+	}
+}
+
+func TestApplyUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	document := map[string]interface{}{"_id": 1, "n": 1, "tags": []interface{}{"a"}}
+
+	updated, err := applyUpdate(document, map[string]interface{}{
+		"$inc":         map[string]interface{}{"n": 2, "visitas": 1},
+		"$set":         map[string]interface{}{"perfil.activo": true},
+		"$addToSet":    map[string]interface{}{"tags": map[string]interface{}{"$each": []interface{}{"a", "b"}}},
+		"$currentDate": map[string]interface{}{"modificado": true},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"_id":        1,
+		"n":          3,
+		"visitas":    1,
+		"perfil":     map[string]interface{}{"activo": true},
+		"tags":       []interface{}{"a", "b"},
+		"modificado": now,
+	}
+	if !equal(updated, want) {
+		t.Fatalf("resultado = %v, se esperaba %v", updated, want)
+	}
+	if document["n"] != 1 {
+		t.Fatal("applyUpdate modificó el documento original")
+	}
+
+	if _, err := applyUpdate(document, map[string]interface{}{"$set": map[string]interface{}{"_id": 2}}, now); err == nil {
+		t.Error("modificar _id debería fallar")
+	}
+	if _, err := applyUpdate(document, map[string]interface{}{"$inc": map[string]interface{}{"tags": 1}}, now); err == nil {
+		t.Error("$inc sobre un array debería fallar")
+	}
+
+	piped, err := applyPipeline(document, []interface{}{
+		map[string]interface{}{"$set": map[string]interface{}{"copia": "$n"}},
+		map[string]interface{}{"$unset": "tags"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(piped, map[string]interface{}{"_id": 1, "n": 1, "copia": 1}) {
+		t.Fatalf("pipeline = %v", piped)
+	}
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+)
+
+// store guarda las bases de datos en memoria. Los documentos de cada
+// colección mantienen el orden de inserción, que es el orden natural en que
+// los devuelve find. Todas las escrituras pasan por write.
+type store struct {
+	mu        sync.RWMutex
+	databases map[string]map[string]*collection
+	// journal, si no es nil, guarda cada escritura antes de aplicarla.
+	journal *journal
+}
+
+type collection struct {
+	documents []map[string]interface{}
+}
+
+// mutation es una escritura sobre el almacén.
+type mutation struct {
+	op         string
+	database   string
+	collection string
+	// id identifica el documento en replace y delete.
+	id       interface{}
+	document map[string]interface{}
+}
+
+const (
+	opCreateDatabase   = "createDatabase"
+	opCreateCollection = "createCollection"
+	opInsert           = "insert"
+	opReplace          = "replace"
+	opDelete           = "delete"
+	opDropCollection   = "dropCollection"
+	opDropDatabase     = "dropDatabase"
+)
+
+func newStore() *store {
+	return &store{databases: make(map[string]map[string]*collection)}
+}
+
+// write registra la escritura en el journal, si lo hay, y la aplica. Si no
+// se puede registrar, el almacén no cambia. Debe llamarse con mu bloqueado
+// para escritura.
+func (s *store) write(m mutation) error {
+	if s.journal != nil {
+		if err := s.journal.append(m); err != nil {
+			return err
+		}
+	}
+	s.apply(m)
+	if s.journal != nil {
+		s.journal.compactIfNeeded(s)
+	}
+	return nil
+}
+
+// apply aplica la escritura en memoria. Debe llamarse con mu bloqueado
+// para escritura.
+func (s *store) apply(m mutation) {
+	switch m.op {
+	case opCreateDatabase:
+		if _, ok := s.databases[m.database]; !ok {
+			s.databases[m.database] = make(map[string]*collection)
+		}
+	case opCreateCollection:
+		s.collection(m.database, m.collection, true)
+	case opInsert:
+		target := s.collection(m.database, m.collection, true)
+		target.documents = append(target.documents, m.document)
+	case opReplace:
+		if target := s.collection(m.database, m.collection, false); target != nil {
+			if i := target.indexOf(m.id); i >= 0 {
+				target.documents[i] = m.document
+			}
+		}
+	case opDelete:
+		if target := s.collection(m.database, m.collection, false); target != nil {
+			if i := target.indexOf(m.id); i >= 0 {
+				target.documents = append(target.documents[:i], target.documents[i+1:]...)
+			}
+		}
+	case opDropCollection:
+		// Sin colecciones, la base de datos deja de existir, como en MongoDB
+		if collections, ok := s.databases[m.database]; ok {
+			delete(collections, m.collection)
+			if len(collections) == 0 {
+				delete(s.databases, m.database)
+			}
+		}
+	case opDropDatabase:
+		delete(s.databases, m.database)
+	}
+}
+
+// collection devuelve la colección; con create, la crea (y su base de
+// datos) si no existe.
+func (s *store) collection(database, name string, create bool) *collection {
+	collections, ok := s.databases[database]
+	if !ok {
+		if !create {
+			return nil
+		}
+		collections = make(map[string]*collection)
+		s.databases[database] = collections
+	}
+
+	target, ok := collections[name]
+	if !ok && create {
+		target = &collection{}
+		collections[name] = target
+	}
+	return target
+}
+
+func (s *store) databaseNames() []string {
+	names := make([]string, 0, len(s.databases))
+	for name := range s.databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *store) collectionNames(database string) []string {
+	names := make([]string, 0, len(s.databases[database]))
+	for name := range s.databases[database] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *collection) indexOf(id interface{}) int {
+	for i, document := range c.documents {
+		if equal(document["_id"], id) {
+			return i
+		}
+	}
+	return -1
+}
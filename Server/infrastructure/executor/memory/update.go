@@ -0,0 +1,448 @@
+package memory
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// applyUpdate devuelve una copia del documento con los operadores de
+// actualización aplicados. El documento original no se modifica.
+func applyUpdate(document map[string]interface{}, update map[string]interface{}, now time.Time) (map[string]interface{}, error) {
+	updated := cloneDocument(document)
+
+	for _, operator := range sortedKeys(update) {
+		fields, _ := update[operator].(map[string]interface{})
+		for _, path := range sortedKeys(fields) {
+			if strings.Contains(path, "$") {
+				return nil, entities.NewDiagnostic(i18n.ExecUnsupportedOperator, path)
+			}
+			if err := applyOperator(updated, operator, path, clone(fields[path]), now); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !equal(document["_id"], updated["_id"]) {
+		return nil, entities.NewDiagnostic(i18n.ExecImmutableID)
+	}
+	return updated, nil
+}
+
+func applyOperator(document map[string]interface{}, operator, path string, operand interface{}, now time.Time) error {
+	current, exists := getPath(document, path)
+
+	switch operator {
+	case "$set":
+		return setPath(document, path, operand)
+	case "$unset":
+		unsetPath(document, path)
+		return nil
+	case "$setOnInsert":
+		return nil // sin upsert nunca se inserta
+	case "$inc", "$mul":
+		if exists && !isNumber(current) {
+			return entities.NewDiagnostic(i18n.ExecUpdateTypeMismatch, operator, path, typeName(current))
+		}
+		if !exists {
+			current = 0
+		}
+		if operator == "$inc" {
+			return setPath(document, path, arithmetic(current, operand, func(a, b float64) float64 { return a + b }, func(a, b int) int { return a + b }))
+		}
+		return setPath(document, path, arithmetic(current, operand, func(a, b float64) float64 { return a * b }, func(a, b int) int { return a * b }))
+	case "$min", "$max":
+		c := compare(operand, current)
+		if !exists || (operator == "$min" && c < 0) || (operator == "$max" && c > 0) {
+			return setPath(document, path, operand)
+		}
+		return nil
+	case "$rename":
+		target, _ := operand.(string)
+		if !exists {
+			return nil
+		}
+		unsetPath(document, path)
+		return setPath(document, target, current)
+	case "$currentDate":
+		return setPath(document, path, now)
+	case "$push", "$addToSet":
+		array, err := arrayAt(operator, path, current, exists)
+		if err != nil {
+			return err
+		}
+		return setPath(document, path, appendValues(operator, array, operand))
+	case "$pop":
+		array, err := arrayAt(operator, path, current, exists)
+		if err != nil || len(array) == 0 {
+			return err
+		}
+		if toFloat(operand) < 0 {
+			return setPath(document, path, array[1:])
+		}
+		return setPath(document, path, array[:len(array)-1])
+	case "$pull", "$pullAll":
+		if !exists {
+			return nil
+		}
+		array, err := arrayAt(operator, path, current, exists)
+		if err != nil {
+			return err
+		}
+		kept := make([]interface{}, 0, len(array))
+		for _, element := range array {
+			remove, err := pulls(operator, element, operand)
+			if err != nil {
+				return err
+			}
+			if !remove {
+				kept = append(kept, element)
+			}
+		}
+		return setPath(document, path, kept)
+	}
+	return entities.NewDiagnostic(i18n.ExecUnsupportedOperator, operator)
+}
+
+// arithmetic suma o multiplica conservando los enteros cuando ambos
+// operandos lo son.
+func arithmetic(a, b interface{}, floats func(a, b float64) float64, ints func(a, b int) int) interface{} {
+	x, xInt := a.(int)
+	y, yInt := b.(int)
+	if xInt && yInt {
+		return ints(x, y)
+	}
+	return floats(toFloat(a), toFloat(b))
+}
+
+func arrayAt(operator, path string, current interface{}, exists bool) ([]interface{}, error) {
+	if !exists {
+		return []interface{}{}, nil
+	}
+	array, ok := current.([]interface{})
+	if !ok {
+		return nil, entities.NewDiagnostic(i18n.ExecUpdateTypeMismatch, operator, path, typeName(current))
+	}
+	return array, nil
+}
+
+// appendValues aplica $push o $addToSet, con los modificadores $each,
+// $position, $slice y $sort de $push.
+func appendValues(operator string, array []interface{}, operand interface{}) []interface{} {
+	values := []interface{}{operand}
+	modifiers, _ := operand.(map[string]interface{})
+	if each, ok := modifiers["$each"].([]interface{}); ok {
+		values = each
+	}
+
+	if operator == "$addToSet" {
+		for _, value := range values {
+			if !containsValue(array, value) {
+				array = append(array, value)
+			}
+		}
+		return array
+	}
+
+	position := len(array)
+	if raw, ok := modifiers["$position"]; ok && isNumber(raw) {
+		position = int(toFloat(raw))
+		if position < 0 {
+			position += len(array)
+		}
+		position = clamp(position, 0, len(array))
+	}
+	result := append([]interface{}{}, array[:position]...)
+	result = append(result, values...)
+	result = append(result, array[position:]...)
+
+	if spec, ok := modifiers["$sort"]; ok {
+		sortArray(result, spec)
+	}
+	if raw, ok := modifiers["$slice"]; ok && isNumber(raw) {
+		limit := int(toFloat(raw))
+		if limit >= 0 && limit < len(result) {
+			result = result[:limit]
+		} else if limit < 0 && -limit < len(result) {
+			result = result[len(result)+limit:]
+		}
+	}
+	return result
+}
+
+// sortArray ordena los elementos por su valor (1 o -1) o por los campos de
+// una especificación { campo: 1 }.
+func sortArray(array []interface{}, spec interface{}) {
+	if isNumber(spec) {
+		direction := int(toFloat(spec))
+		sort.SliceStable(array, func(i, j int) bool { return compare(array[i], array[j])*direction < 0 })
+		return
+	}
+	fields, _ := spec.(map[string]interface{})
+	keys := sortedKeys(fields)
+	sort.SliceStable(array, func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := getPath(asDocument(array[i]), key)
+			b, _ := getPath(asDocument(array[j]), key)
+			if c := compare(a, b) * int(toFloat(fields[key])); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+func asDocument(value interface{}) map[string]interface{} {
+	document, _ := value.(map[string]interface{})
+	return document
+}
+
+// pulls indica si $pull o $pullAll eliminan el elemento.
+func pulls(operator string, element, operand interface{}) (bool, error) {
+	if operator == "$pullAll" {
+		values, _ := operand.([]interface{})
+		return containsValue(values, element), nil
+	}
+
+	condition, isDocument := operand.(map[string]interface{})
+	switch {
+	case isDocument && hasOperatorKeys(condition) && !isLogicalFilter(condition):
+		return matchOperators([]interface{}{element}, condition)
+	case isDocument:
+		if document, ok := element.(map[string]interface{}); ok {
+			return matches(document, condition)
+		}
+		return false, nil
+	}
+	return equal(element, operand), nil
+}
+
+func containsValue(array []interface{}, value interface{}) bool {
+	for _, element := range array {
+		if equal(element, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func clamp(value, low, high int) int {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
+
+// getPath lee el valor exacto de una ruta, sin expandir arrays salvo por
+// índice.
+func getPath(document map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = document
+	for _, segment := range strings.Split(path, ".") {
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			value, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return nil, false
+			}
+			current = typed[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setPath asigna el valor creando los subdocumentos intermedios que falten.
+func setPath(document map[string]interface{}, path string, value interface{}) error {
+	segments := strings.Split(path, ".")
+	var current interface{} = document
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch typed := current.(type) {
+		case map[string]interface{}:
+			if last {
+				typed[segment] = value
+				return nil
+			}
+			next, ok := typed[segment]
+			if !ok || next == nil {
+				next = map[string]interface{}{}
+				typed[segment] = next
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) {
+				return entities.NewDiagnostic(i18n.ExecUpdateTypeMismatch, "$set", path, "array")
+			}
+			if last {
+				typed[index] = value
+				return nil
+			}
+			current = typed[index]
+		default:
+			return entities.NewDiagnostic(i18n.ExecUpdateTypeMismatch, "$set", path, typeName(current))
+		}
+	}
+	return nil
+}
+
+func unsetPath(document map[string]interface{}, path string) {
+	segments := strings.Split(path, ".")
+	parent, ok := getPath(document, strings.Join(segments[:len(segments)-1], "."))
+	if len(segments) == 1 {
+		parent, ok = document, true
+	}
+	if !ok {
+		return
+	}
+	last := segments[len(segments)-1]
+	switch typed := parent.(type) {
+	case map[string]interface{}:
+		delete(typed, last)
+	case []interface{}:
+		// En un array $unset deja null en la posición, como MongoDB
+		if index, err := strconv.Atoi(last); err == nil && index >= 0 && index < len(typed) {
+			typed[index] = nil
+		}
+	}
+}
+
+// applyPipeline aplica una actualización con pipeline. Se admiten las etapas
+// $set/$addFields, $unset, $project y $replaceRoot/$replaceWith; las
+// expresiones pueden ser literales o referencias "$campo".
+func applyPipeline(document map[string]interface{}, pipeline []interface{}) (map[string]interface{}, error) {
+	updated := cloneDocument(document)
+
+	for _, rawStage := range pipeline {
+		stage, _ := rawStage.(map[string]interface{})
+		for name, spec := range stage {
+			switch name {
+			case "$set", "$addFields":
+				fields, _ := spec.(map[string]interface{})
+				for _, path := range sortedKeys(fields) {
+					if err := setPath(updated, path, evaluate(updated, fields[path])); err != nil {
+						return nil, err
+					}
+				}
+			case "$unset":
+				for _, path := range stringList(spec) {
+					unsetPath(updated, path)
+				}
+			case "$project":
+				updated = project(updated, spec)
+			case "$replaceRoot", "$replaceWith":
+				if name == "$replaceRoot" {
+					spec = asDocument(spec)["newRoot"]
+				}
+				root, ok := evaluate(updated, spec).(map[string]interface{})
+				if !ok {
+					return nil, entities.NewDiagnostic(i18n.ExecUpdateTypeMismatch, name, "newRoot", typeName(root))
+				}
+				if _, hasID := root["_id"]; !hasID {
+					root["_id"] = document["_id"]
+				}
+				updated = root
+			default:
+				return nil, entities.NewDiagnostic(i18n.ExecUnsupportedOperator, name)
+			}
+		}
+	}
+
+	if !equal(document["_id"], updated["_id"]) {
+		return nil, entities.NewDiagnostic(i18n.ExecImmutableID)
+	}
+	return updated, nil
+}
+
+// evaluate resuelve las referencias "$campo" de una expresión.
+func evaluate(document map[string]interface{}, expression interface{}) interface{} {
+	switch typed := expression.(type) {
+	case string:
+		if strings.HasPrefix(typed, "$") && !strings.HasPrefix(typed, "$$") {
+			value, _ := getPath(document, typed[1:])
+			return clone(value)
+		}
+	case map[string]interface{}:
+		if literal, ok := typed["$literal"]; ok && len(typed) == 1 {
+			return literal
+		}
+		evaluated := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			evaluated[key] = evaluate(document, value)
+		}
+		return evaluated
+	case []interface{}:
+		evaluated := make([]interface{}, len(typed))
+		for i, value := range typed {
+			evaluated[i] = evaluate(document, value)
+		}
+		return evaluated
+	}
+	return expression
+}
+
+// project aplica una proyección de inclusión o de exclusión. _id se incluye
+// salvo que se excluya expresamente.
+func project(document map[string]interface{}, spec interface{}) map[string]interface{} {
+	fields, _ := spec.(map[string]interface{})
+	inclusion := false
+	for key, value := range fields {
+		if key != "_id" && truthy(value) {
+			inclusion = true
+		}
+	}
+
+	if !inclusion {
+		projected := cloneDocument(document)
+		for key, value := range fields {
+			if !truthy(value) {
+				unsetPath(projected, key)
+			}
+		}
+		return projected
+	}
+
+	projected := map[string]interface{}{}
+	if include, ok := fields["_id"]; !ok || truthy(include) {
+		projected["_id"] = document["_id"]
+	}
+	for key, value := range fields {
+		if key == "_id" || !truthy(value) {
+			continue
+		}
+		if current, ok := getPath(document, key); ok {
+			setPath(projected, key, clone(current))
+		}
+	}
+	return projected
+}
+
+func stringList(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		var list []string
+		for _, element := range typed {
+			if text, ok := element.(string); ok {
+				list = append(list, text)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Los documentos se guardan con los tipos que produce el parser (int,
+// float64, string, bool, nil, map[string]interface{}, []interface{}) más
+// primitive.ObjectID para los _id generados y time.Time para $currentDate.
+
+// clone copia un valor en profundidad, de modo que el almacén nunca
+// comparta mapas ni slices con el comando o con el resultado.
+func clone(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return cloneDocument(typed)
+	case []interface{}:
+		array := make([]interface{}, len(typed))
+		for i, element := range typed {
+			array[i] = clone(element)
+		}
+		return array
+	}
+	return value
+}
+
+func cloneDocument(document map[string]interface{}) map[string]interface{} {
+	if document == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(document))
+	for key, value := range document {
+		copied[key] = clone(value)
+	}
+	return copied
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int32, int64, float64:
+		return true
+	}
+	return false
+}
+
+func toFloat(value interface{}) float64 {
+	switch n := value.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return math.NaN()
+}
+
+// typeName devuelve el alias BSON del valor, el que usan $type y los
+// mensajes de error.
+func typeName(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int:
+		if typed > math.MaxInt32 || typed < math.MinInt32 {
+			return "long"
+		}
+		return "int"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case primitive.ObjectID:
+		return "objectId"
+	case time.Time:
+		return "date"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// typeOrder es el orden en que MongoDB compara valores de tipos distintos.
+func typeOrder(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 1
+	case int, int32, int64, float64:
+		return 2
+	case string:
+		return 3
+	case map[string]interface{}:
+		return 4
+	case []interface{}:
+		return 5
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case time.Time:
+		return 9
+	}
+	return 100
+}
+
+// compare ordena dos valores como MongoDB: primero por tipo y después por
+// valor.
+func compare(a, b interface{}) int {
+	if orderA, orderB := typeOrder(a), typeOrder(b); orderA != orderB {
+		return sign(orderA - orderB)
+	}
+
+	switch typedA := a.(type) {
+	case nil:
+		return 0
+	case int, int32, int64, float64:
+		x, y := toFloat(a), toFloat(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case string:
+		typedB := b.(string)
+		switch {
+		case typedA < typedB:
+			return -1
+		case typedA > typedB:
+			return 1
+		}
+		return 0
+	case bool:
+		typedB := b.(bool)
+		if typedA == typedB {
+			return 0
+		}
+		if !typedA {
+			return -1
+		}
+		return 1
+	case primitive.ObjectID:
+		typedB := b.(primitive.ObjectID)
+		return sign(compareBytes(typedA[:], typedB[:]))
+	case time.Time:
+		typedB := b.(time.Time)
+		return sign(typedA.Compare(typedB))
+	case []interface{}:
+		typedB := b.([]interface{})
+		for i := 0; i < len(typedA) && i < len(typedB); i++ {
+			if c := compare(typedA[i], typedB[i]); c != 0 {
+				return c
+			}
+		}
+		return sign(len(typedA) - len(typedB))
+	case map[string]interface{}:
+		typedB := b.(map[string]interface{})
+		keysA, keysB := sortedKeys(typedA), sortedKeys(typedB)
+		for i := 0; i < len(keysA) && i < len(keysB); i++ {
+			if keysA[i] != keysB[i] {
+				if keysA[i] < keysB[i] {
+					return -1
+				}
+				return 1
+			}
+			if c := compare(typedA[keysA[i]], typedB[keysB[i]]); c != 0 {
+				return c
+			}
+		}
+		return sign(len(keysA) - len(keysB))
+	}
+	return 0
+}
+
+func equal(a, b interface{}) bool {
+	return typeOrder(a) == typeOrder(b) && compare(a, b) == 0
+}
+
+func compareBytes(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return int(a[i]) - int(b[i])
+		}
+	}
+	return len(a) - len(b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys(document map[string]interface{}) []string {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func hasOperatorKeys(document map[string]interface{}) bool {
+	for key := range document {
+		if len(key) > 0 && key[0] == '$' {
+			return true
+		}
+	}
+	return false
+}
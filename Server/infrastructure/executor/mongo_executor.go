@@ -6,22 +6,32 @@ import (
 	"time"
 
 	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+	"mongo-analyzer/infrastructure/cursor"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// fieldSampleSize es el número de documentos que se leen para descubrir campos.
+const fieldSampleSize = 50
+
 type MongoExecutor struct {
 	client         *mongo.Client
 	connectionURI  string
-	currentDB      string
 	timeout        time.Duration
+	cursors        interfaces.CursorRegistry
 }
 
-func NewMongoExecutor(connectionURI string) *MongoExecutor {
+// NewMongoExecutor crea el executor. timeout limita cada operación contra
+// el servidor. Con cursors, find devuelve el primer lote y un cursor para
+// los siguientes; sin él (nil), todos los documentos.
+func NewMongoExecutor(connectionURI string, timeout time.Duration, cursors interfaces.CursorRegistry) *MongoExecutor {
 	return &MongoExecutor{
 		connectionURI: connectionURI,
-		timeout:       10 * time.Second,
+		timeout:       timeout,
+		cursors:       cursors,
 	}
 }
 
@@ -52,12 +62,14 @@ func (e *MongoExecutor) Close() error {
 	return nil
 }
 
-func (e *MongoExecutor) Execute(command *entities.MongoCommand) (interface{}, error) {
+// Execute ejecuta command con ctx. El timeout del executor se aplica además
+// del plazo que ya traiga ctx, así que actúa como límite máximo.
+func (e *MongoExecutor) Execute(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
 	if e.client == nil {
-		return nil, fmt.Errorf("no hay conexión a MongoDB")
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
 
 	switch command.Type {
@@ -71,6 +83,8 @@ func (e *MongoExecutor) Execute(command *entities.MongoCommand) (interface{}, er
 		return e.executeFind(ctx, command)
 	case entities.UPDATE_ONE:
 		return e.executeUpdateOne(ctx, command)
+	case entities.REPLACE_ONE:
+		return e.executeReplaceOne(ctx, command)
 	case entities.DELETE_ONE:
 		return e.executeDeleteOne(ctx, command)
 	case entities.DROP_COLLECTION:
@@ -78,13 +92,13 @@ func (e *MongoExecutor) Execute(command *entities.MongoCommand) (interface{}, er
 	case entities.DROP_DATABASE:
 		return e.executeDropDatabase(ctx, command)
 	default:
-		return nil, fmt.Errorf("tipo de comando no soportado")
+		return nil, entities.NewDiagnostic(i18n.ExecUnsupportedCommand)
 	}
 }
 
 func (e *MongoExecutor) executeUseDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	e.currentDB = command.Database
-	
+	entities.SessionFromContext(ctx).SetDatabase(command.Database)
+
 	// Verificar si la base de datos existe o crearla
 	db := e.client.Database(command.Database)
 	
@@ -99,146 +113,176 @@ func (e *MongoExecutor) executeUseDatabase(ctx context.Context, command *entitie
 	tempCollection.Drop(ctx)
 	
 	return map[string]interface{}{
-		"message":  fmt.Sprintf("Cambiado a base de datos '%s'", command.Database),
+		"message":  entities.NewDiagnostic(i18n.ExecDatabaseSwitched, command.Database),
 		"database": command.Database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeCreateCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
 	}
 
-	db := e.client.Database(e.currentDB)
+	db := e.client.Database(database)
 	err := db.CreateCollection(ctx, command.Collection)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":    fmt.Sprintf("Colección '%s' creada exitosamente", command.Collection),
+		"message":    entities.NewDiagnostic(i18n.ExecCollectionCreated, command.Collection),
 		"collection": command.Collection,
-		"database":   e.currentDB,
+		"database":   database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeInsertOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
 	}
 
-	collection := e.client.Database(e.currentDB).Collection(command.Collection)
+	collection := e.client.Database(database).Collection(command.Collection)
 	result, err := collection.InsertOne(ctx, command.Document)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":     "Documento insertado exitosamente",
+		"message":     entities.NewDiagnostic(i18n.ExecDocumentInserted),
 		"insertedId":  result.InsertedID,
 		"collection":  command.Collection,
-		"database":    e.currentDB,
+		"database":    database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeFind(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+	iterator, err := e.Stream(ctx, command)
+	if err != nil {
+		return nil, err
 	}
 
-	collection := e.client.Database(e.currentDB).Collection(command.Collection)
-	
+	page, err := cursor.FirstPage(ctx, e.cursors, iterator, e.databaseOf(ctx, command), command.Collection)
+	if err != nil {
+		return nil, err
+	}
+	return cursor.FindResult(page), nil
+}
+
+// Stream abre un cursor del servidor con el filtro de un FIND y lo
+// devuelve como iterador; quien lo recibe debe cerrarlo.
+func (e *MongoExecutor) Stream(ctx context.Context, command *entities.MongoCommand) (interfaces.DocumentIterator, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	collection := e.client.Database(database).Collection(command.Collection)
+
 	filter := bson.M{}
 	if command.Filter != nil {
 		filter = command.Filter
 	}
 
-	cursor, err := collection.Find(ctx, filter)
+	found, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
+	return &mongoIterator{cursor: found, timeout: e.timeout}, nil
+}
 
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
+func (e *MongoExecutor) executeUpdateOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	var update interface{} = command.Update
+	if command.UpdatePipeline != nil {
+		update = command.UpdatePipeline
+	}
+
+	collection := e.client.Database(database).Collection(command.Collection)
+	result, err := collection.UpdateOne(ctx, command.Filter, update)
+	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":    fmt.Sprintf("Encontrados %d documentos", len(results)),
-		"documents":  results,
-		"count":      len(results),
-		"collection": command.Collection,
-		"database":   e.currentDB,
+		"message":       entities.NewDiagnostic(i18n.ExecUpdateCompleted),
+		"matchedCount":  result.MatchedCount,
+		"modifiedCount": result.ModifiedCount,
+		"collection":    command.Collection,
+		"database":      database,
 	}, nil
 }
 
-func (e *MongoExecutor) executeUpdateOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+func (e *MongoExecutor) executeReplaceOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
 	}
 
-	collection := e.client.Database(e.currentDB).Collection(command.Collection)
-	result, err := collection.UpdateOne(ctx, command.Filter, command.Update)
+	collection := e.client.Database(database).Collection(command.Collection)
+	result, err := collection.ReplaceOne(ctx, command.Filter, command.Document)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":       "Actualización completada",
+		"message":       entities.NewDiagnostic(i18n.ExecReplaceCompleted),
 		"matchedCount":  result.MatchedCount,
 		"modifiedCount": result.ModifiedCount,
 		"collection":    command.Collection,
-		"database":      e.currentDB,
+		"database":      database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeDeleteOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
 	}
 
-	collection := e.client.Database(e.currentDB).Collection(command.Collection)
+	collection := e.client.Database(database).Collection(command.Collection)
 	result, err := collection.DeleteOne(ctx, command.Filter)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":      "Eliminación completada",
+		"message":      entities.NewDiagnostic(i18n.ExecDeleteCompleted),
 		"deletedCount": result.DeletedCount,
 		"collection":   command.Collection,
-		"database":     e.currentDB,
+		"database":     database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeDropCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
-	if e.currentDB == "" {
-		return nil, fmt.Errorf("no hay base de datos seleccionada. Usa 'use nombreDB' primero")
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
 	}
 
-	collection := e.client.Database(e.currentDB).Collection(command.Collection)
+	collection := e.client.Database(database).Collection(command.Collection)
 	err := collection.Drop(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"message":    fmt.Sprintf("Colección '%s' eliminada exitosamente", command.Collection),
+		"message":    entities.NewDiagnostic(i18n.ExecCollectionDropped, command.Collection),
 		"collection": command.Collection,
-		"database":   e.currentDB,
+		"database":   database,
 	}, nil
 }
 
 func (e *MongoExecutor) executeDropDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
 	// ✅ MEJORADO: Si no hay database especificada, usar la actual
-	var databaseName string
-	if command.Database != "" {
-		databaseName = command.Database
-	} else if e.currentDB != "" {
-		databaseName = e.currentDB
-	} else {
-		return nil, fmt.Errorf("no hay base de datos especificada o seleccionada. Usa 'use nombreDB' primero o especifica la base de datos")
+	databaseName := e.databaseOf(ctx, command)
+	if databaseName == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabaseToDrop)
 	}
 
 	db := e.client.Database(databaseName)
@@ -247,13 +291,90 @@ func (e *MongoExecutor) executeDropDatabase(ctx context.Context, command *entiti
 		return nil, err
 	}
 
-	// ✅ Si se eliminó la DB actual, limpiar currentDB
-	if databaseName == e.currentDB {
-		e.currentDB = ""
-	}
+	// ✅ Si se eliminó la DB actual de la sesión, olvidarla
+	entities.SessionFromContext(ctx).ClearDatabase(databaseName)
 
 	return map[string]interface{}{
-		"message":  fmt.Sprintf("Base de datos '%s' eliminada exitosamente", databaseName),
+		"message":  entities.NewDiagnostic(i18n.ExecDatabaseDropped, databaseName),
 		"database": databaseName,
 	}, nil
-}
\ No newline at end of file
+}
+
+// CurrentDatabase devuelve la base de datos actual de la sesión de ctx.
+func (e *MongoExecutor) CurrentDatabase(ctx context.Context) string {
+	return entities.SessionFromContext(ctx).Database()
+}
+
+// databaseOf devuelve la base de datos del comando o, si no indica ninguna,
+// la actual de la sesión.
+func (e *MongoExecutor) databaseOf(ctx context.Context, command *entities.MongoCommand) string {
+	if command.Database != "" {
+		return command.Database
+	}
+	return e.CurrentDatabase(ctx)
+}
+
+func (e *MongoExecutor) ListDatabases(ctx context.Context) ([]string, error) {
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	return e.client.ListDatabaseNames(ctx, bson.M{})
+}
+
+// ListCollections devuelve las colecciones de la base de datos actual.
+func (e *MongoExecutor) ListCollections(ctx context.Context) ([]string, error) {
+	database := e.CurrentDatabase(ctx)
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	return e.client.Database(database).ListCollectionNames(ctx, bson.M{})
+}
+
+// ListFields devuelve los campos de primer nivel observados en una muestra
+// de documentos de la colección.
+func (e *MongoExecutor) ListFields(ctx context.Context, collection string) ([]string, error) {
+	database := e.CurrentDatabase(ctx)
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	cursor, err := e.client.Database(database).Collection(collection).Find(ctx, bson.M{}, options.Find().SetLimit(fieldSampleSize))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var fields []string
+	seen := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		for key := range doc {
+			if !seen[key] {
+				seen[key] = true
+				fields = append(fields, key)
+			}
+		}
+	}
+
+	return fields, cursor.Err()
+}
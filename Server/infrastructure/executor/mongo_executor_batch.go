@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// WriteBatch envía el lote en una sola operación bulkWrite no ordenada: un
+// documento que falla (por ejemplo por un _id repetido) no impide escribir
+// los demás.
+func (e *MongoExecutor) WriteBatch(ctx context.Context, database, collection string, writes []entities.BatchWrite) ([]entities.WriteOutcome, error) {
+	if len(writes) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	models := make([]mongo.WriteModel, len(writes))
+	for i, write := range writes {
+		if write.Filter == nil {
+			models[i] = mongo.NewInsertOneModel().SetDocument(write.Document)
+			continue
+		}
+		models[i] = mongo.NewReplaceOneModel().SetFilter(write.Filter).SetReplacement(write.Document).SetUpsert(true)
+	}
+
+	target := e.client.Database(database).Collection(collection)
+	result, err := target.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	outcomes := make([]entities.WriteOutcome, len(writes))
+	if err != nil {
+		var exception mongo.BulkWriteException
+		if !errors.As(err, &exception) || exception.WriteConcernError != nil || result == nil {
+			return nil, err
+		}
+		for _, failed := range exception.WriteErrors {
+			outcomes[failed.Index].Err = failed
+		}
+	}
+
+	// Un reemplazo sin _id en UpsertedIDs encontró un documento existente
+	for i, write := range writes {
+		if write.Filter == nil || outcomes[i].Err != nil {
+			continue
+		}
+		if _, inserted := result.UpsertedIDs[int64(i)]; !inserted {
+			outcomes[i].Updated = true
+		}
+	}
+	return outcomes, nil
+}
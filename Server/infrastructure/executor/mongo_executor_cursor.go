@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoIterator lee un cursor del servidor por lotes. Cada lote tiene su
+// propio timeout, porque se pide en una petición HTTP distinta.
+type mongoIterator struct {
+	cursor  *mongo.Cursor
+	timeout time.Duration
+}
+
+func (i *mongoIterator) Next(ctx context.Context, size int) ([]map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+
+	var documents []map[string]interface{}
+	for len(documents) < size && i.cursor.Next(ctx) {
+		var doc bson.M
+		if err := i.cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		documents = append(documents, doc)
+	}
+	return documents, i.cursor.Err()
+}
+
+func (i *mongoIterator) Close(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), i.timeout)
+	defer cancel()
+	return i.cursor.Close(ctx)
+}
@@ -0,0 +1,201 @@
+package executor
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// DryRun informa qué haría command sin modificar la base de datos. Solo
+// realiza lecturas: conteos de documentos y listados de colecciones.
+func (e *MongoExecutor) DryRun(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	switch command.Type {
+	case entities.USE_DATABASE:
+		return e.dryRunUseDatabase(ctx, command)
+	case entities.CREATE_COLLECTION:
+		return e.dryRunCreateCollection(ctx, command)
+	case entities.INSERT_ONE:
+		return e.dryRunInsertOne(ctx, command)
+	case entities.FIND:
+		return e.dryRunMatch(ctx, command, 0, i18n.DryRunFind)
+	case entities.UPDATE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunUpdate)
+	case entities.REPLACE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunReplace)
+	case entities.DELETE_ONE:
+		return e.dryRunMatch(ctx, command, 1, i18n.DryRunDelete)
+	case entities.DROP_COLLECTION:
+		return e.dryRunDropCollection(ctx, command)
+	case entities.DROP_DATABASE:
+		return e.dryRunDropDatabase(ctx, command)
+	default:
+		return nil, entities.NewDiagnostic(i18n.ExecUnsupportedCommand)
+	}
+}
+
+func (e *MongoExecutor) dryRunUseDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	exists, err := e.databaseExists(ctx, command.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":  entities.NewDiagnostic(i18n.DryRunUseDatabase, command.Database),
+		"dryRun":   true,
+		"database": command.Database,
+		"exists":   exists,
+	}, nil
+}
+
+func (e *MongoExecutor) dryRunCreateCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	exists, err := e.collectionExists(ctx, database, command.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":    entities.NewDiagnostic(i18n.DryRunCreateCollection, command.Collection),
+		"dryRun":     true,
+		"collection": command.Collection,
+		"database":   database,
+		"exists":     exists,
+	}, nil
+}
+
+func (e *MongoExecutor) dryRunInsertOne(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	exists, err := e.collectionExists(ctx, database, command.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":          entities.NewDiagnostic(i18n.DryRunInsert, command.Collection),
+		"dryRun":           true,
+		"insertedCount":    1,
+		"collectionExists": exists,
+		"collection":       command.Collection,
+		"database":         database,
+	}, nil
+}
+
+// dryRunMatch cuenta los documentos que coinciden con el filtro. limit acota
+// el conteo a lo que el comando realmente tocaría (1 para updateOne y
+// deleteOne); 0 significa sin límite.
+func (e *MongoExecutor) dryRunMatch(ctx context.Context, command *entities.MongoCommand, limit int64, code string) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	filter := bson.M{}
+	if command.Filter != nil {
+		filter = command.Filter
+	}
+
+	countOptions := options.Count()
+	if limit > 0 {
+		countOptions.SetLimit(limit)
+	}
+
+	collection := e.client.Database(database).Collection(command.Collection)
+	matched, err := collection.CountDocuments(ctx, filter, countOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":      entities.NewDiagnostic(code, matched),
+		"dryRun":       true,
+		"matchedCount": matched,
+		"collection":   command.Collection,
+		"database":     database,
+	}, nil
+}
+
+func (e *MongoExecutor) dryRunDropCollection(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	database := e.databaseOf(ctx, command)
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	count, err := e.client.Database(database).Collection(command.Collection).EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":       entities.NewDiagnostic(i18n.DryRunDropCollection, command.Collection, count),
+		"dryRun":        true,
+		"documentCount": count,
+		"collection":    command.Collection,
+		"database":      database,
+	}, nil
+}
+
+func (e *MongoExecutor) dryRunDropDatabase(ctx context.Context, command *entities.MongoCommand) (interface{}, error) {
+	databaseName := e.databaseOf(ctx, command)
+	if databaseName == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabaseToDrop)
+	}
+
+	db := e.client.Database(databaseName)
+	names, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		count, err := db.Collection(name).EstimatedDocumentCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, map[string]interface{}{
+			"collection":    name,
+			"documentCount": count,
+		})
+	}
+
+	return map[string]interface{}{
+		"message":     entities.NewDiagnostic(i18n.DryRunDropDatabase, databaseName, len(collections)),
+		"dryRun":      true,
+		"collections": collections,
+		"database":    databaseName,
+	}, nil
+}
+
+func (e *MongoExecutor) databaseExists(ctx context.Context, name string) (bool, error) {
+	names, err := e.client.ListDatabaseNames(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
+func (e *MongoExecutor) collectionExists(ctx context.Context, database, name string) (bool, error) {
+	names, err := e.client.Database(database).ListCollectionNames(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
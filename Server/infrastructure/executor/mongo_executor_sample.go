@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// SampleDocuments lee una muestra aleatoria de la colección con $sample.
+func (e *MongoExecutor) SampleDocuments(ctx context.Context, database, collection string, size int) ([]map[string]interface{}, error) {
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{{{Key: "$sample", Value: bson.M{"size": size}}}}
+	cursor, err := e.client.Database(database).Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []map[string]interface{}
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		if document, ok := plainValue(doc).(map[string]interface{}); ok {
+			documents = append(documents, document)
+		}
+	}
+	return documents, cursor.Err()
+}
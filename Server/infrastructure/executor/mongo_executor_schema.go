@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// CollectionSchema devuelve el $jsonSchema del validador que la colección
+// tiene configurado en el servidor (listCollections), o nil si no tiene.
+func (e *MongoExecutor) CollectionSchema(ctx context.Context, database, collection string) (map[string]interface{}, error) {
+	if e.client == nil {
+		return nil, entities.NewDiagnostic(i18n.ExecNotConnected)
+	}
+	if database == "" {
+		return nil, entities.NewDiagnostic(i18n.ExecNoDatabase)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	specifications, err := e.client.Database(database).ListCollectionSpecifications(ctx, bson.M{"name": collection})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, specification := range specifications {
+		var options struct {
+			Validator struct {
+				JSONSchema bson.M `bson:"$jsonSchema"`
+			} `bson:"validator"`
+		}
+		if specification.Options == nil {
+			continue
+		}
+		if err := bson.Unmarshal(specification.Options, &options); err != nil {
+			return nil, err
+		}
+		if options.Validator.JSONSchema != nil {
+			schema, _ := plainValue(options.Validator.JSONSchema).(map[string]interface{})
+			return schema, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// plainValue convierte los tipos del driver (bson.M, bson.D, bson.A, int32,
+// int64) a los que produce el parser, para validar ambos con el mismo código.
+func plainValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case primitive.M:
+		document := make(map[string]interface{}, len(typed))
+		for key, element := range typed {
+			document[key] = plainValue(element)
+		}
+		return document
+	case primitive.D:
+		document := make(map[string]interface{}, len(typed))
+		for _, element := range typed {
+			document[element.Key] = plainValue(element.Value)
+		}
+		return document
+	case primitive.A:
+		array := make([]interface{}, len(typed))
+		for i, element := range typed {
+			array[i] = plainValue(element)
+		}
+		return array
+	case int32:
+		return int(typed)
+	case int64:
+		return int(typed)
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/infrastructure/render"
+)
+
+// ColumnSample es el número de documentos de los que se deducen las
+// columnas de un csv o un xlsx cuando no se indican.
+const ColumnSample = 1000
+
+// Columns devuelve las columnas de una tabla con los documentos: los campos
+// de los subdocumentos se aplanan con puntos (direccion.ciudad). Las
+// columnas aparecen en el orden en que se encuentran, con los campos de
+// cada documento ordenados y _id siempre primero.
+func Columns(documents []map[string]interface{}) []string {
+	var columns []string
+	seen := map[string]bool{}
+	for _, document := range documents {
+		flatten("", document, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+	}
+	return columns
+}
+
+// MissingColumns devuelve las columnas de document que no están en known y
+// las añade a known, de modo que cada una se informa una sola vez.
+func MissingColumns(document map[string]interface{}, known map[string]bool) []string {
+	var missing []string
+	flatten("", document, func(column string) {
+		if !known[column] {
+			known[column] = true
+			missing = append(missing, column)
+		}
+	})
+	return missing
+}
+
+func flatten(prefix string, document map[string]interface{}, add func(string)) {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "_id") != (keys[j] == "_id") {
+			return keys[i] == "_id"
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		if nested, ok := asDocument(document[key]); ok && len(nested) > 0 {
+			flatten(prefix+key+".", nested, add)
+			continue
+		}
+		add(prefix + key)
+	}
+}
+
+// asDocument devuelve value como mapa si es un subdocumento, venga del
+// driver (bson.M, bson.D) o del parser.
+func asDocument(value interface{}) (map[string]interface{}, bool) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return typed, true
+	case primitive.M:
+		return typed, true
+	case primitive.D:
+		return typed.Map(), true
+	}
+	return nil, false
+}
+
+// Lookup devuelve el valor de una ruta con puntos. Una ruta que no llega a
+// un campo (o que atraviesa un array) no existe.
+func Lookup(document map[string]interface{}, path string) (interface{}, bool) {
+	current := document
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		value, ok := current[segment]
+		if !ok {
+			return nil, false
+		}
+		if i == len(segments)-1 {
+			return value, true
+		}
+		if current, ok = asDocument(value); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// Project devuelve un documento con solo las columnas indicadas, anidadas
+// como en el original. Sin columnas devuelve el documento completo.
+func Project(document map[string]interface{}, columns []string) map[string]interface{} {
+	if len(columns) == 0 {
+		return document
+	}
+
+	projected := map[string]interface{}{}
+	for _, column := range columns {
+		value, ok := Lookup(document, column)
+		if !ok {
+			continue
+		}
+		target := projected
+		segments := strings.Split(column, ".")
+		for _, segment := range segments[:len(segments)-1] {
+			next, ok := target[segment].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[segment] = next
+			}
+			target = next
+		}
+		target[segments[len(segments)-1]] = value
+	}
+	return projected
+}
+
+var cellEncoder = render.NewExtendedJSONEncoder(render.ExtendedJSONRelaxed)
+
+// Cell escribe un valor como texto de una celda. Los tipos que no tienen
+// una forma natural en una tabla (arrays, subdocumentos, binarios...) se
+// escriben como Extended JSON relajado.
+func Cell(value interface{}) string {
+	switch typed := value.(type) {
+	case nil, primitive.Null, primitive.Undefined:
+		return ""
+	case string:
+		return typed
+	case bool:
+		return strconv.FormatBool(typed)
+	case int:
+		return strconv.Itoa(typed)
+	case int32:
+		return strconv.FormatInt(int64(typed), 10)
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case float64:
+		if math.IsInf(typed, 0) || math.IsNaN(typed) {
+			break
+		}
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case primitive.ObjectID:
+		return typed.Hex()
+	case primitive.DateTime:
+		return formatDate(typed.Time())
+	case time.Time:
+		return formatDate(typed)
+	case primitive.Decimal128:
+		return typed.String()
+	}
+	return cellEncoder.Encode(value)
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+
+	"mongo-analyzer/domain/interfaces"
+	"mongo-analyzer/infrastructure/render"
+)
+
+// Format describe un formato de exportación.
+type Format struct {
+	ContentType string
+	Extension   string
+	// Tabular indica que el formato necesita columnas; si no se eligen, se
+	// toman del primer lote con Columns.
+	Tabular bool
+	New     func(w io.Writer, columns []string) interfaces.ExportWriter
+}
+
+// Formats son los formatos que admite GET /export.
+var Formats = map[string]Format{
+	"csv":    {ContentType: "text/csv; charset=utf-8", Extension: "csv", Tabular: true, New: NewCSVWriter},
+	"ndjson": {ContentType: "application/x-ndjson", Extension: "ndjson", New: NewNDJSONWriter},
+	"json":   {ContentType: "application/json", Extension: "json", New: NewJSONWriter},
+	"xlsx":   {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx", Tabular: true, New: NewXLSXWriter},
+}
+
+// CSVWriter escribe una fila de cabecera con las columnas y una fila por
+// documento. Los campos que faltan quedan vacíos.
+type CSVWriter struct {
+	csv     *csv.Writer
+	columns []string
+	started bool
+}
+
+func NewCSVWriter(w io.Writer, columns []string) interfaces.ExportWriter {
+	return &CSVWriter{csv: csv.NewWriter(w), columns: columns}
+}
+
+func (c *CSVWriter) Write(document map[string]interface{}) error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	row := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		if value, ok := Lookup(document, column); ok {
+			row[i] = Cell(value)
+		}
+	}
+	return c.csv.Write(row)
+}
+
+func (c *CSVWriter) header() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.csv.Write(c.columns)
+}
+
+func (c *CSVWriter) Flush() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// Close escribe la cabecera si no había documentos.
+func (c *CSVWriter) Close() error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// NDJSONWriter escribe un documento por línea en Extended JSON relajado.
+type NDJSONWriter struct {
+	out     *bufio.Writer
+	columns []string
+	encoder *render.ExtendedJSONEncoder
+}
+
+func NewNDJSONWriter(w io.Writer, columns []string) interfaces.ExportWriter {
+	return &NDJSONWriter{out: bufio.NewWriter(w), columns: columns, encoder: render.NewExtendedJSONEncoder(render.ExtendedJSONRelaxed)}
+}
+
+func (n *NDJSONWriter) Write(document map[string]interface{}) error {
+	n.out.WriteString(n.encoder.Encode(Project(document, n.columns)))
+	return n.out.WriteByte('\n')
+}
+
+func (n *NDJSONWriter) Flush() error {
+	return n.out.Flush()
+}
+
+func (n *NDJSONWriter) Close() error {
+	return n.out.Flush()
+}
+
+// JSONWriter escribe un array de documentos en Extended JSON relajado, un
+// documento por línea.
+type JSONWriter struct {
+	out     *bufio.Writer
+	columns []string
+	encoder *render.ExtendedJSONEncoder
+	written bool
+}
+
+func NewJSONWriter(w io.Writer, columns []string) interfaces.ExportWriter {
+	return &JSONWriter{out: bufio.NewWriter(w), columns: columns, encoder: render.NewExtendedJSONEncoder(render.ExtendedJSONRelaxed)}
+}
+
+func (j *JSONWriter) Write(document map[string]interface{}) error {
+	separator := ",\n"
+	if !j.written {
+		separator = "[\n"
+		j.written = true
+	}
+	j.out.WriteString(separator)
+	_, err := j.out.WriteString(j.encoder.Encode(Project(document, j.columns)))
+	return err
+}
+
+func (j *JSONWriter) Flush() error {
+	return j.out.Flush()
+}
+
+func (j *JSONWriter) Close() error {
+	if j.written {
+		j.out.WriteString("\n]\n")
+	} else {
+		j.out.WriteString("[]\n")
+	}
+	return j.out.Flush()
+}
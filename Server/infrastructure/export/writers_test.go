@@ -0,0 +1,55 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCSVWriterFlattensNestedFields(t *testing.T) {
+	documents := []map[string]interface{}{
+		{"_id": 1, "nombre": "Ana, la primera", "dir": bson.M{"ciudad": "Lima", "cp": 15001}, "tags": bson.A{"x", "y"}},
+		{"_id": 2, "nombre": "Luis", "nota": 7.5},
+	}
+
+	var out strings.Builder
+	writer := NewCSVWriter(&out, Columns(documents))
+	for _, document := range documents {
+		if err := writer.Write(document); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "_id,dir.ciudad,dir.cp,nombre,tags,nota\n" +
+		"1,Lima,15001,\"Ana, la primera\",\"[\"\"x\"\",\"\"y\"\"]\",\n" +
+		"2,,,Luis,,7.5\n"
+	if out.String() != want {
+		t.Fatalf("csv:\n%s\nse esperaba:\n%s", out.String(), want)
+	}
+}
+
+func TestProjectKeepsSelectedPaths(t *testing.T) {
+	document := map[string]interface{}{"_id": 1, "dir": bson.M{"ciudad": "Lima", "cp": 15001}}
+
+	projected := Project(document, []string{"dir.ciudad", "falta"})
+	dir, ok := projected["dir"].(map[string]interface{})
+	if len(projected) != 1 || !ok || len(dir) != 1 || dir["ciudad"] != "Lima" {
+		t.Fatalf("proyección = %v", projected)
+	}
+}
+
+func TestMissingColumnsReportsEachFieldOnce(t *testing.T) {
+	known := map[string]bool{"_id": true, "nombre": true}
+
+	missing := MissingColumns(map[string]interface{}{"_id": 3, "nombre": "Eva", "dir": bson.M{"ciudad": "Quito"}, "nota": 9}, known)
+	if strings.Join(missing, ",") != "dir.ciudad,nota" {
+		t.Fatalf("columnas nuevas = %v", missing)
+	}
+	if again := MissingColumns(map[string]interface{}{"_id": 4, "nota": 5}, known); len(again) != 0 {
+		t.Fatalf("columnas ya informadas = %v", again)
+	}
+}
@@ -0,0 +1,178 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+)
+
+// Límites de Excel para una hoja.
+const (
+	xlsxMaxRows      = 1048576
+	xlsxMaxCellChars = 32767
+)
+
+// Partes fijas del libro: una sola hoja, sin estilos ni cadenas
+// compartidas. Las celdas de texto van en línea (inlineStr), de modo que la
+// hoja se puede escribir fila a fila.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="export" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter escribe un libro de Excel con una hoja: la cabecera con las
+// columnas y una fila por documento. Los números y booleanos se guardan
+// como tales; el resto, como texto.
+type XLSXWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	columns []string
+	row     int
+	err     error
+}
+
+func NewXLSXWriter(w io.Writer, columns []string) interfaces.ExportWriter {
+	return &XLSXWriter{zip: zip.NewWriter(w), columns: columns}
+}
+
+func (x *XLSXWriter) Write(document map[string]interface{}) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	if x.row == xlsxMaxRows {
+		return entities.NewDiagnostic(i18n.ExportSheetFull, xlsxMaxRows-1)
+	}
+
+	values := make([]interface{}, len(x.columns))
+	for i, column := range x.columns {
+		values[i], _ = Lookup(document, column)
+	}
+	x.writeRow(values)
+	return x.err
+}
+
+// start escribe las partes fijas, abre la hoja y escribe la cabecera.
+func (x *XLSXWriter) start() error {
+	if x.sheet != nil || x.err != nil {
+		return x.err
+	}
+
+	for _, part := range xlsxParts {
+		file, err := x.zip.Create(part.name)
+		if err != nil {
+			x.err = err
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			x.err = err
+			return err
+		}
+	}
+
+	file, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return err
+	}
+	x.sheet = bufio.NewWriter(file)
+	x.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]interface{}, len(x.columns))
+	for i, column := range x.columns {
+		header[i] = column
+	}
+	x.writeRow(header)
+	return x.err
+}
+
+func (x *XLSXWriter) writeRow(values []interface{}) {
+	x.row++
+	number := strconv.Itoa(x.row)
+	x.sheet.WriteString(`<row r="` + number + `">`)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		reference := columnName(i) + number
+		switch typed := value.(type) {
+		case bool:
+			flag := "0"
+			if typed {
+				flag = "1"
+			}
+			x.sheet.WriteString(`<c r="` + reference + `" t="b"><v>` + flag + `</v></c>`)
+			continue
+		case int, int32, int64:
+			x.sheet.WriteString(`<c r="` + reference + `"><v>` + Cell(typed) + `</v></c>`)
+			continue
+		case float64:
+			if !math.IsInf(typed, 0) && !math.IsNaN(typed) {
+				x.sheet.WriteString(`<c r="` + reference + `"><v>` + Cell(typed) + `</v></c>`)
+				continue
+			}
+		}
+
+		text := Cell(value)
+		if len(text) > xlsxMaxCellChars {
+			text = strings.ToValidUTF8(text[:xlsxMaxCellChars], "")
+		}
+		x.sheet.WriteString(`<c r="` + reference + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(x.sheet, []byte(text))
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, x.err = x.sheet.WriteString(`</row>`)
+}
+
+// columnName convierte un índice en el nombre de columna de Excel (0 → A,
+// 26 → AA).
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func (x *XLSXWriter) Flush() error {
+	if x.sheet == nil || x.err != nil {
+		return x.err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Flush()
+}
+
+// Close escribe el final de la hoja y el índice del archivo zip.
+func (x *XLSXWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
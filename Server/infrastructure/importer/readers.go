@@ -0,0 +1,266 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
+)
+
+// maxLineBytes es el tamaño máximo de una línea NDJSON.
+const maxLineBytes = 16 << 20
+
+// Formats son los formatos que admite POST /import, con la extensión y el
+// tipo MIME por los que se reconocen si no se indica format.
+var Formats = map[string]struct{ Extension, ContentType string }{
+	"csv":    {".csv", "text/csv"},
+	"json":   {".json", "application/json"},
+	"ndjson": {".ndjson", "application/x-ndjson"},
+}
+
+// Options controla cómo se convierten los valores de un CSV. JSON y NDJSON
+// se leen como Extended JSON y ya llevan sus tipos.
+type Options struct {
+	// Types fija el tipo de algunas columnas.
+	Types map[string]FieldType
+	// Infer deduce el tipo del resto de columnas; sin él son texto.
+	Infer bool
+}
+
+// NewReader crea el lector de un archivo en el formato indicado (csv, json
+// o ndjson).
+func NewReader(format string, r io.Reader, options Options) (interfaces.RowReader, error) {
+	switch format {
+	case "csv":
+		return NewCSVReader(r, options)
+	case "json":
+		return NewJSONReader(r), nil
+	case "ndjson":
+		return NewNDJSONReader(r), nil
+	}
+	return nil, entities.NewDiagnostic(i18n.HTTPInvalidFormat, format, "csv, json, ndjson")
+}
+
+// CSVReader lee un CSV con una fila de cabecera. Las columnas con puntos
+// (direccion.ciudad) crean subdocumentos y las celdas vacías se omiten.
+type CSVReader struct {
+	csv     *csv.Reader
+	columns []string
+	types   []FieldType
+}
+
+func NewCSVReader(r io.Reader, options Options) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = entities.NewDiagnostic(i18n.ImportEmptyFile)
+		}
+		return nil, err
+	}
+
+	columns := make([]string, len(header))
+	types := make([]FieldType, len(header))
+	seen := map[string]bool{}
+	for i, column := range header {
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		column = strings.TrimSpace(column)
+		if column == "" || seen[column] || strings.HasPrefix(column, "$") {
+			return nil, entities.NewDiagnostic(i18n.ImportInvalidHeader, column)
+		}
+		seen[column] = true
+		columns[i] = column
+
+		types[i] = TypeString
+		if options.Infer {
+			types[i] = TypeAuto
+		}
+		if fieldType, ok := options.Types[column]; ok {
+			types[i] = fieldType
+		}
+	}
+
+	// Una columna no puede ser a la vez un valor y un subdocumento (a y a.b)
+	for _, column := range columns {
+		for prefix := column; strings.Contains(prefix, "."); {
+			prefix = prefix[:strings.LastIndex(prefix, ".")]
+			if seen[prefix] {
+				return nil, entities.NewDiagnostic(i18n.ImportInvalidHeader, column)
+			}
+		}
+	}
+
+	return &CSVReader{csv: reader, columns: columns, types: types}, nil
+}
+
+func (c *CSVReader) Next() (*entities.ImportRow, error) {
+	record, err := c.csv.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return &entities.ImportRow{Row: parseError.StartLine, Err: entities.NewDiagnostic(i18n.ImportInvalidRow, parseError.Err)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := c.csv.FieldPos(0)
+	row := &entities.ImportRow{Row: line, Document: map[string]interface{}{}}
+	for i, cell := range record {
+		if cell == "" {
+			continue
+		}
+		value, err := convert(c.columns[i], cell, c.types[i])
+		if err != nil {
+			row.Document, row.Err = nil, err
+			return row, nil
+		}
+		setPath(row.Document, c.columns[i], value)
+	}
+	return row, nil
+}
+
+func setPath(document map[string]interface{}, path string, value interface{}) {
+	segments := strings.Split(path, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := document[segment].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			document[segment] = next
+		}
+		document = next
+	}
+	document[segments[len(segments)-1]] = value
+}
+
+// NDJSONReader lee un documento Extended JSON por línea. Las líneas en
+// blanco se ignoran.
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineBytes)
+	return &NDJSONReader{scanner: scanner}
+}
+
+func (n *NDJSONReader) Next() (*entities.ImportRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return decodeRow(n.line, line), nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// JSONReader lee un array JSON de documentos o, como mongoimport, una
+// sucesión de documentos separados por espacios. Los documentos se leen de
+// uno en uno, sin cargar el archivo entero.
+type JSONReader struct {
+	input   *bufio.Reader
+	decoder *json.Decoder
+	started bool
+	array   bool
+	row     int
+}
+
+func NewJSONReader(r io.Reader) *JSONReader {
+	input := bufio.NewReader(r)
+	return &JSONReader{input: input, decoder: json.NewDecoder(input)}
+}
+
+func (j *JSONReader) Next() (*entities.ImportRow, error) {
+	if !j.started {
+		j.started = true
+		if err := j.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	if j.array && !j.decoder.More() {
+		if _, err := j.decoder.Token(); err != nil { // ]
+			return nil, invalidJSON(err)
+		}
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := j.decoder.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) && !j.array {
+			return nil, io.EOF
+		}
+		return nil, invalidJSON(err)
+	}
+	j.row++
+	return decodeRow(j.row, raw), nil
+}
+
+// open mira el primer carácter que no es un espacio: con [ el archivo es
+// un array.
+func (j *JSONReader) open() error {
+	if bom, _ := j.input.Peek(3); string(bom) == "\ufeff" {
+		j.input.Discard(3)
+	}
+	for {
+		next, err := j.input.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch next[0] {
+		case ' ', '\t', '\r', '\n':
+			j.input.Discard(1)
+			continue
+		}
+
+		j.array = next[0] == '['
+		if j.array {
+			j.decoder.Token()
+		}
+		return nil
+	}
+}
+
+// invalidJSON traduce los errores de sintaxis; los de lectura (por ejemplo
+// un archivo demasiado grande) se devuelven tal cual.
+func invalidJSON(err error) error {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return entities.NewDiagnostic(i18n.ImportInvalidJSON, err)
+	}
+	return err
+}
+
+func decodeRow(row int, data []byte) *entities.ImportRow {
+	var document bson.M
+	if err := bson.UnmarshalExtJSON(data, false, &document); err != nil {
+		return &entities.ImportRow{Row: row, Err: entities.NewDiagnostic(i18n.ImportInvalidRow, err)}
+	}
+	return &entities.ImportRow{Row: row, Document: normalizeDocument(document)}
+}
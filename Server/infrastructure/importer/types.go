@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+const (
+	// DefaultBatchSize es el número de documentos que se escriben de una vez.
+	DefaultBatchSize = 500
+	// DefaultMaxBytes es el tamaño máximo de un archivo importado.
+	DefaultMaxBytes = 256 << 20
+)
+
+// FieldType es el tipo al que se convierte una columna de un CSV.
+type FieldType string
+
+const (
+	TypeAuto     FieldType = "auto"
+	TypeString   FieldType = "string"
+	TypeInt      FieldType = "int"
+	TypeLong     FieldType = "long"
+	TypeDouble   FieldType = "double"
+	TypeBool     FieldType = "bool"
+	TypeDate     FieldType = "date"
+	TypeObjectID FieldType = "objectId"
+)
+
+var fieldTypes = map[FieldType]bool{
+	TypeAuto: true, TypeString: true, TypeInt: true, TypeLong: true,
+	TypeDouble: true, TypeBool: true, TypeDate: true, TypeObjectID: true,
+}
+
+// ParseTypes interpreta una lista de tipos por columna como
+// "edad:int,alta:date".
+func ParseTypes(spec string) (map[string]FieldType, error) {
+	types := map[string]FieldType{}
+	if strings.TrimSpace(spec) == "" {
+		return types, nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		column, name, _ := strings.Cut(strings.TrimSpace(item), ":")
+		fieldType := FieldType(name)
+		if column == "" || !fieldTypes[fieldType] {
+			return nil, entities.NewDiagnostic(i18n.ImportInvalidType, column, name)
+		}
+		types[column] = fieldType
+	}
+	return types, nil
+}
+
+// Un número con ceros a la izquierda (un código postal, un teléfono) se
+// deja como texto al inferir.
+var (
+	integerPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)\.[0-9]+$`)
+)
+
+// infer deduce el tipo de un valor de un CSV: booleano, número, fecha
+// ISO-8601 o, si no es nada de eso, texto.
+func infer(value string) interface{} {
+	switch {
+	case value == "true" || value == "false":
+		return value == "true"
+	case integerPattern.MatchString(value):
+		if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if number >= math.MinInt32 && number <= math.MaxInt32 {
+				return int(number)
+			}
+			return number
+		}
+	case decimalPattern.MatchString(value):
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	}
+	if date, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return date.UTC()
+	}
+	return value
+}
+
+// convert convierte un valor de un CSV al tipo de su columna.
+func convert(column, value string, fieldType FieldType) (interface{}, error) {
+	invalid := func() error {
+		return entities.NewDiagnostic(i18n.ImportInvalidValue, column, value, fieldType)
+	}
+
+	switch fieldType {
+	case TypeAuto:
+		return infer(value), nil
+	case TypeString:
+		return value, nil
+	case TypeInt:
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, invalid()
+		}
+		return int(number), nil
+	case TypeLong:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, invalid()
+		}
+		return number, nil
+	case TypeDouble:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, invalid()
+		}
+		return number, nil
+	case TypeBool:
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalid()
+		}
+		return flag, nil
+	case TypeDate:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date.UTC(), nil
+			}
+		}
+		return nil, invalid()
+	case TypeObjectID:
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, invalid()
+		}
+		return id, nil
+	}
+	return value, nil
+}
+
+// normalize convierte lo que devuelve el decodificador de Extended JSON a
+// los mismos tipos que produce el parser: mapas, []interface{}, int y
+// time.Time.
+func normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case primitive.M:
+		return normalizeDocument(typed)
+	case map[string]interface{}:
+		return normalizeDocument(typed)
+	case primitive.D:
+		return normalizeDocument(typed.Map())
+	case primitive.A:
+		items := make([]interface{}, len(typed))
+		for i, item := range typed {
+			items[i] = normalize(item)
+		}
+		return items
+	case int32:
+		return int(typed)
+	case primitive.DateTime:
+		return typed.Time().UTC()
+	}
+	return value
+}
+
+func normalizeDocument(document map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(document))
+	for key, value := range document {
+		normalized[key] = normalize(value)
+	}
+	return normalized
+}
@@ -1,39 +1,43 @@
 package lexer
 
 import (
-	"fmt"
 	"strings"
 	"unicode"
 
 	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
 )
 
-type MongoLexer struct {
+// MongoLexer no guarda estado entre llamadas: cada Tokenize usa su propio
+// scanner, de modo que una sola instancia puede compartirse entre
+// peticiones concurrentes.
+type MongoLexer struct{}
+
+func NewMongoLexer() *MongoLexer {
+	return &MongoLexer{}
+}
+
+// scanner es el estado de una llamada a Tokenize.
+type scanner struct {
 	input    string
 	position int
 	line     int
 	column   int
 }
 
-func NewMongoLexer() *MongoLexer {
-	return &MongoLexer{
+func (m *MongoLexer) Tokenize(input string) ([]*entities.Token, error) {
+	l := &scanner{
+		input:  strings.TrimSpace(input),
 		line:   1,
 		column: 1,
 	}
-}
-
-func (l *MongoLexer) Tokenize(input string) ([]*entities.Token, error) {
-	l.input = strings.TrimSpace(input)
-	l.position = 0
-	l.line = 1
-	l.column = 1
 
 	var tokens []*entities.Token
 
 	for l.position < len(l.input) {
 		token := l.nextToken()
 		if token.Type == entities.INVALID {
-			return nil, fmt.Errorf("token inválido en posición %d: '%s'", token.Position, token.Value)
+			return nil, entities.NewDiagnostic(i18n.LexInvalidToken, token.Position, token.Value)
 		}
 		if token.Type != entities.EOF {
 			tokens = append(tokens, token)
@@ -51,7 +55,7 @@ func (l *MongoLexer) Tokenize(input string) ([]*entities.Token, error) {
 	return tokens, nil
 }
 
-func (l *MongoLexer) nextToken() *entities.Token {
+func (l *scanner) nextToken() *entities.Token {
 	l.skipWhitespace()
 
 	if l.position >= len(l.input) {
@@ -86,11 +90,17 @@ func (l *MongoLexer) nextToken() *entities.Token {
 	case '$':
 		l.advance()
 		return &entities.Token{Type: entities.DOLLAR_SIGN, Value: "$", Position: start, Line: l.line, Column: l.column - 1}
+	case '[':
+		l.advance()
+		return &entities.Token{Type: entities.LEFT_BRACKET, Value: "[", Position: start, Line: l.line, Column: l.column - 1}
+	case ']':
+		l.advance()
+		return &entities.Token{Type: entities.RIGHT_BRACKET, Value: "]", Position: start, Line: l.line, Column: l.column - 1}
 	case '"':
 		return l.readString()
 	}
 
-	if unicode.IsDigit(rune(ch)) {
+	if unicode.IsDigit(rune(ch)) || (ch == '-' && l.position+1 < len(l.input) && unicode.IsDigit(rune(l.input[l.position+1]))) {
 		return l.readNumber()
 	}
 
@@ -102,7 +112,7 @@ func (l *MongoLexer) nextToken() *entities.Token {
 	return &entities.Token{Type: entities.INVALID, Value: string(ch), Position: start}
 }
 
-func (l *MongoLexer) readString() *entities.Token {
+func (l *scanner) readString() *entities.Token {
 	start := l.position
 	l.advance() // skip opening quote
 
@@ -120,10 +130,15 @@ func (l *MongoLexer) readString() *entities.Token {
 	return &entities.Token{Type: entities.STRING, Value: value, Position: start, Line: l.line, Column: l.column - len(value) - 2}
 }
 
-func (l *MongoLexer) readNumber() *entities.Token {
+func (l *scanner) readNumber() *entities.Token {
 	start := l.position
 	value := ""
 
+	if l.input[l.position] == '-' {
+		value = "-"
+		l.advance()
+	}
+
 	for l.position < len(l.input) && (unicode.IsDigit(rune(l.input[l.position])) || l.input[l.position] == '.') {
 		value += string(l.input[l.position])
 		l.advance()
@@ -132,7 +147,7 @@ func (l *MongoLexer) readNumber() *entities.Token {
 	return &entities.Token{Type: entities.NUMBER, Value: value, Position: start, Line: l.line, Column: l.column - len(value)}
 }
 
-func (l *MongoLexer) readIdentifier() *entities.Token {
+func (l *scanner) readIdentifier() *entities.Token {
 	start := l.position
 	value := ""
 
@@ -145,16 +160,19 @@ func (l *MongoLexer) readIdentifier() *entities.Token {
 	return &entities.Token{Type: tokenType, Value: value, Position: start, Line: l.line, Column: l.column - len(value)}
 }
 
-func (l *MongoLexer) getIdentifierType(value string) entities.TokenType {
+func (l *scanner) getIdentifierType(value string) entities.TokenType {
 	switch value {
 	case "use":
 		return entities.USE
 	case "db":
 		return entities.DB
+	case "true", "false":
+		return entities.BOOLEAN
+	case "null":
+		return entities.NULL
 	default:
 		// Verificar si es una función conocida
-		functions := []string{"createCollection", "insertOne", "find", "updateOne", "deleteOne", "drop", "dropDatabase"}
-		for _, fn := range functions {
+		for _, fn := range entities.KnownFunctions {
 			if value == fn {
 				return entities.FUNCTION
 			}
@@ -163,19 +181,30 @@ func (l *MongoLexer) getIdentifierType(value string) entities.TokenType {
 	}
 }
 
-func (l *MongoLexer) skipWhitespace() {
-	for l.position < len(l.input) && unicode.IsSpace(rune(l.input[l.position])) {
-		if l.input[l.position] == '\n' {
+// skipWhitespace salta espacios y comentarios de línea (// ...). Los
+// comentarios pueden llevar directivas para el linter, que se leen del texto
+// original.
+func (l *scanner) skipWhitespace() {
+	for l.position < len(l.input) {
+		switch ch := l.input[l.position]; {
+		case ch == '\n':
 			l.line++
 			l.column = 1
-		} else {
+			l.position++
+		case unicode.IsSpace(rune(ch)):
 			l.column++
+			l.position++
+		case strings.HasPrefix(l.input[l.position:], "//"):
+			for l.position < len(l.input) && l.input[l.position] != '\n' {
+				l.advance()
+			}
+		default:
+			return
 		}
-		l.position++
 	}
 }
 
-func (l *MongoLexer) advance() {
+func (l *scanner) advance() {
 	if l.position < len(l.input) {
 		l.position++
 		l.column++
@@ -1,75 +1,135 @@
 package parser
 
 import (
-	"fmt"
+	"context"
 	"strconv"
 	"strings"
 
 	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
 )
 
-type MongoParser struct {
+// MongoParser no guarda estado entre llamadas: cada Parse usa su propio
+// parseState, de modo que una sola instancia puede compartirse entre
+// peticiones concurrentes.
+type MongoParser struct{}
+
+// parseState es el estado de una llamada a Parse.
+type parseState struct {
 	tokens   []*entities.Token
 	position int
 	current  *entities.Token
+	// nodes es la pila de producciones abiertas del árbol de derivación.
+	nodes []*entities.ASTNode
+	// functions es la pila paralela con el método que analiza cada producción.
+	functions  []string
+	derivation *entities.DerivationLog
 }
 
 func NewMongoParser() *MongoParser {
 	return &MongoParser{}
 }
 
-func (p *MongoParser) Parse(tokens []*entities.Token) (*entities.MongoCommand, error) {
-	p.tokens = tokens
-	p.position = 0
-	p.current = p.tokens[0]
+// Parse construye el comando y su árbol de derivación. Si ctx lleva un
+// DerivationLog, además registra cada paso del análisis.
+func (m *MongoParser) Parse(ctx context.Context, tokens []*entities.Token) (*entities.MongoCommand, error) {
+	// holder solo sirve de padre para la producción inicial
+	holder := &entities.ASTNode{}
+	p := &parseState{
+		tokens:     tokens,
+		current:    tokens[0],
+		nodes:      []*entities.ASTNode{holder},
+		functions:  []string{""},
+		derivation: entities.DerivationFromContext(ctx),
+	}
 
 	command, err := p.parseCommand()
 	if err != nil {
 		return nil, err
 	}
 
+	// La gramática acepta un solo comando: lo que quede detrás es un error,
+	// pero el árbol del comando ya analizado se conserva
+	if command.IsValid && p.current.Type != entities.EOF {
+		command.IsValid = false
+		command.Errors = append(command.Errors, entities.NewDiagnostic(i18n.ParseTrailingTokens, p.current.Value, p.current.Position))
+	}
+
 	command.TokenCount = len(tokens) - 1 // Excluir EOF
+	command.AST = holder.Children[0]
 	return command, nil
 }
 
-func (p *MongoParser) parseCommand() (*entities.MongoCommand, error) {
+func (p *parseState) parseCommand() (*entities.MongoCommand, error) {
+	p.enter("command", "parseCommand")
+	defer p.leave()
+
 	if p.current.Type == entities.USE {
+		p.branch("use_command")
 		return p.parseUseCommand()
 	}
 
 	if p.current.Type == entities.DB {
+		p.branch("db_command")
 		return p.parseDbCommand()
 	}
 
-	return nil, fmt.Errorf("comando no reconocido: %s", p.current.Value)
+	return nil, entities.NewDiagnostic(i18n.ParseUnknownCommand, p.current.Value)
 }
 
-func (p *MongoParser) parseUseCommand() (*entities.MongoCommand, error) {
+func (p *parseState) parseUseCommand() (*entities.MongoCommand, error) {
+	p.enter("use_command", "parseUseCommand")
+	defer p.leave()
+
 	p.advance() // skip 'use'
 
 	if p.current.Type != entities.IDENTIFIER {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba nombre de base de datos después de 'use'"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedDatabaseName)},
 		}, nil
 	}
 
 	dbName := p.current.Value
 	p.advance()
 
-	// Verificar si hay dropDatabase después
+	// Verificar si hay dropDatabase después; cualquier otra cosa detrás de
+	// 'db' es un error
 	if p.current.Type == entities.DB {
 		p.advance() // skip 'db'
-		if p.current.Type == entities.DOT {
-			p.advance() // skip '.'
-			if p.current.Type == entities.FUNCTION && p.current.Value == "dropDatabase" {
+		if p.current.Type != entities.DOT {
+			return &entities.MongoCommand{
+				IsValid: false,
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedDotAfterDB)},
+			}, nil
+		}
+		p.advance() // skip '.'
+		if p.current.Type != entities.FUNCTION || p.current.Value != "dropDatabase" {
+			return &entities.MongoCommand{
+				IsValid: false,
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseInvalidDBCommand)},
+			}, nil
+		}
+		p.branch("drop_database")
+		p.advance() // skip 'dropDatabase'
+
+		// Los paréntesis son opcionales: use x db.dropDatabase
+		if p.current.Type == entities.LEFT_PAREN {
+			p.advance()
+			if p.current.Type != entities.RIGHT_PAREN {
 				return &entities.MongoCommand{
-					Type:     entities.DROP_DATABASE,
-					Database: dbName,
-					IsValid:  true,
+					IsValid: false,
+					Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "dropDatabase")},
 				}, nil
 			}
+			p.advance()
 		}
+
+		return &entities.MongoCommand{
+			Type:     entities.DROP_DATABASE,
+			Database: dbName,
+			IsValid:  true,
+		}, nil
 	}
 
 	return &entities.MongoCommand{
@@ -79,19 +139,23 @@ func (p *MongoParser) parseUseCommand() (*entities.MongoCommand, error) {
 	}, nil
 }
 
-func (p *MongoParser) parseDbCommand() (*entities.MongoCommand, error) {
+func (p *parseState) parseDbCommand() (*entities.MongoCommand, error) {
+	p.enter("db_command", "parseDbCommand")
+	defer p.leave()
+
 	p.advance() // skip 'db'
 
 	if p.current.Type != entities.DOT {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '.' después de 'db'"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedDotAfterDB)},
 		}, nil
 	}
 	p.advance() // skip '.'
 
 	// ✅ NUEVO: Manejar db.createCollection() y db.dropDatabase()
 	if p.current.Type == entities.FUNCTION {
+		p.branch(p.current.Value)
 		switch p.current.Value {
 		case "createCollection":
 			return p.parseCreateCollection()
@@ -100,19 +164,20 @@ func (p *MongoParser) parseDbCommand() (*entities.MongoCommand, error) {
 		default:
 			return &entities.MongoCommand{
 				IsValid: false,
-				Errors:  []string{fmt.Sprintf("Función no reconocida: %s", p.current.Value)},
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseUnknownFunction, p.current.Value)},
 			}, nil
 		}
 	}
 
 	if p.current.Type == entities.IDENTIFIER {
+		p.branch("collection_command")
 		collection := p.current.Value
 		p.advance()
 
 		if p.current.Type != entities.DOT {
 			return &entities.MongoCommand{
 				IsValid: false,
-				Errors:  []string{"Se esperaba '.' después del nombre de la colección"},
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedDotAfterCollection)},
 			}, nil
 		}
 		p.advance() // skip '.'
@@ -120,10 +185,11 @@ func (p *MongoParser) parseDbCommand() (*entities.MongoCommand, error) {
 		if p.current.Type != entities.FUNCTION {
 			return &entities.MongoCommand{
 				IsValid: false,
-				Errors:  []string{"Se esperaba función después de '.'"},
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedFunction)},
 			}, nil
 		}
 
+		p.branch(p.current.Value)
 		switch p.current.Value {
 		case "insertOne":
 			return p.parseInsertOne(collection)
@@ -131,6 +197,8 @@ func (p *MongoParser) parseDbCommand() (*entities.MongoCommand, error) {
 			return p.parseFind(collection)
 		case "updateOne":
 			return p.parseUpdateOne(collection)
+		case "replaceOne":
+			return p.parseReplaceOne(collection)
 		case "deleteOne":
 			return p.parseDeleteOne(collection)
 		case "drop":
@@ -138,24 +206,27 @@ func (p *MongoParser) parseDbCommand() (*entities.MongoCommand, error) {
 		default:
 			return &entities.MongoCommand{
 				IsValid: false,
-				Errors:  []string{fmt.Sprintf("Función no reconocida: %s", p.current.Value)},
+				Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseUnknownFunction, p.current.Value)},
 			}, nil
 		}
 	}
 
 	return &entities.MongoCommand{
 		IsValid: false,
-		Errors:  []string{"Comando db inválido"},
+		Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseInvalidDBCommand)},
 	}, nil
 }
 
-func (p *MongoParser) parseCreateCollection() (*entities.MongoCommand, error) {
+func (p *parseState) parseCreateCollection() (*entities.MongoCommand, error) {
+	p.enter("create_collection", "parseCreateCollection")
+	defer p.leave()
+
 	p.advance() // skip 'createCollection'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de createCollection"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "createCollection")},
 		}, nil
 	}
 	p.advance()
@@ -163,7 +234,7 @@ func (p *MongoParser) parseCreateCollection() (*entities.MongoCommand, error) {
 	if p.current.Type != entities.STRING {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba nombre de colección como string"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedCollectionString)},
 		}, nil
 	}
 
@@ -173,9 +244,10 @@ func (p *MongoParser) parseCreateCollection() (*entities.MongoCommand, error) {
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después del nombre de la colección"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParenCollection)},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.CREATE_COLLECTION,
@@ -184,13 +256,16 @@ func (p *MongoParser) parseCreateCollection() (*entities.MongoCommand, error) {
 	}, nil
 }
 
-func (p *MongoParser) parseInsertOne(collection string) (*entities.MongoCommand, error) {
+func (p *parseState) parseInsertOne(collection string) (*entities.MongoCommand, error) {
+	p.enter("insert_one", "parseInsertOne")
+	defer p.leave()
+
 	p.advance() // skip 'insertOne'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de insertOne"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "insertOne")},
 		}, nil
 	}
 	p.advance()
@@ -199,16 +274,17 @@ func (p *MongoParser) parseInsertOne(collection string) (*entities.MongoCommand,
 	if err != nil {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{err.Error()},
+			Errors:  []*entities.Diagnostic{asDiagnostic(err)},
 		}, nil
 	}
 
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después del documento"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParenDocument)},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.INSERT_ONE,
@@ -218,13 +294,16 @@ func (p *MongoParser) parseInsertOne(collection string) (*entities.MongoCommand,
 	}, nil
 }
 
-func (p *MongoParser) parseFind(collection string) (*entities.MongoCommand, error) {
+func (p *parseState) parseFind(collection string) (*entities.MongoCommand, error) {
+	p.enter("find", "parseFind")
+	defer p.leave()
+
 	p.advance() // skip 'find'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de find"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "find")},
 		}, nil
 	}
 	p.advance()
@@ -234,11 +313,12 @@ func (p *MongoParser) parseFind(collection string) (*entities.MongoCommand, erro
 
 	// find() puede tener filtro opcional
 	if p.current.Type == entities.LEFT_BRACE {
+		p.branch("filter")
 		filter, err = p.parseDocument()
 		if err != nil {
 			return &entities.MongoCommand{
 				IsValid: false,
-				Errors:  []string{err.Error()},
+				Errors:  []*entities.Diagnostic{asDiagnostic(err)},
 			}, nil
 		}
 	}
@@ -246,9 +326,10 @@ func (p *MongoParser) parseFind(collection string) (*entities.MongoCommand, erro
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después de find"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "find")},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.FIND,
@@ -258,13 +339,16 @@ func (p *MongoParser) parseFind(collection string) (*entities.MongoCommand, erro
 	}, nil
 }
 
-func (p *MongoParser) parseUpdateOne(collection string) (*entities.MongoCommand, error) {
+func (p *parseState) parseUpdateOne(collection string) (*entities.MongoCommand, error) {
+	p.enter("update_one", "parseUpdateOne")
+	defer p.leave()
+
 	p.advance() // skip 'updateOne'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de updateOne"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "updateOne")},
 		}, nil
 	}
 	p.advance()
@@ -274,50 +358,118 @@ func (p *MongoParser) parseUpdateOne(collection string) (*entities.MongoCommand,
 	if err != nil {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Error en filtro: " + err.Error()},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseFilterError, err)},
 		}, nil
 	}
 
 	if p.current.Type != entities.COMMA {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ',' entre filtro y actualización"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedCommaFilterUpdate)},
 		}, nil
 	}
 	p.advance()
 
-	// Parse update
-	update, err := p.parseDocument()
+	// Parse update: documento de operadores o pipeline de etapas
+	var update map[string]interface{}
+	var pipeline []interface{}
+	if p.current.Type == entities.LEFT_BRACKET {
+		p.branch("pipeline")
+		pipeline, err = p.parseArray()
+	} else {
+		p.branch("document")
+		update, err = p.parseDocument()
+	}
 	if err != nil {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Error en actualización: " + err.Error()},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseUpdateError, err)},
 		}, nil
 	}
 
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después de updateOne"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "updateOne")},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.UPDATE_ONE,
 		Collection: collection,
+		Filter:         filter,
+		Update:         update,
+		UpdatePipeline: pipeline,
+		IsValid:        true,
+	}, nil
+}
+
+func (p *parseState) parseReplaceOne(collection string) (*entities.MongoCommand, error) {
+	p.enter("replace_one", "parseReplaceOne")
+	defer p.leave()
+
+	p.advance() // skip 'replaceOne'
+
+	if p.current.Type != entities.LEFT_PAREN {
+		return &entities.MongoCommand{
+			IsValid: false,
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "replaceOne")},
+		}, nil
+	}
+	p.advance()
+
+	filter, err := p.parseDocument()
+	if err != nil {
+		return &entities.MongoCommand{
+			IsValid: false,
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseFilterError, err)},
+		}, nil
+	}
+
+	if p.current.Type != entities.COMMA {
+		return &entities.MongoCommand{
+			IsValid: false,
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedCommaFilterReplacement)},
+		}, nil
+	}
+	p.advance()
+
+	replacement, err := p.parseDocument()
+	if err != nil {
+		return &entities.MongoCommand{
+			IsValid: false,
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseReplacementError, err)},
+		}, nil
+	}
+
+	if p.current.Type != entities.RIGHT_PAREN {
+		return &entities.MongoCommand{
+			IsValid: false,
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "replaceOne")},
+		}, nil
+	}
+	p.advance() // skip ')'
+
+	return &entities.MongoCommand{
+		Type:       entities.REPLACE_ONE,
+		Collection: collection,
 		Filter:     filter,
-		Update:     update,
+		Document:   replacement,
 		IsValid:    true,
 	}, nil
 }
 
-func (p *MongoParser) parseDeleteOne(collection string) (*entities.MongoCommand, error) {
+func (p *parseState) parseDeleteOne(collection string) (*entities.MongoCommand, error) {
+	p.enter("delete_one", "parseDeleteOne")
+	defer p.leave()
+
 	p.advance() // skip 'deleteOne'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de deleteOne"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "deleteOne")},
 		}, nil
 	}
 	p.advance()
@@ -326,16 +478,17 @@ func (p *MongoParser) parseDeleteOne(collection string) (*entities.MongoCommand,
 	if err != nil {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{err.Error()},
+			Errors:  []*entities.Diagnostic{asDiagnostic(err)},
 		}, nil
 	}
 
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después del filtro"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParenFilter)},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.DELETE_ONE,
@@ -345,13 +498,16 @@ func (p *MongoParser) parseDeleteOne(collection string) (*entities.MongoCommand,
 	}, nil
 }
 
-func (p *MongoParser) parseDrop(collection string) (*entities.MongoCommand, error) {
+func (p *parseState) parseDrop(collection string) (*entities.MongoCommand, error) {
+	p.enter("drop", "parseDrop")
+	defer p.leave()
+
 	p.advance() // skip 'drop'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de drop"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "drop")},
 		}, nil
 	}
 	p.advance()
@@ -359,9 +515,10 @@ func (p *MongoParser) parseDrop(collection string) (*entities.MongoCommand, erro
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después de drop"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "drop")},
 		}, nil
 	}
+	p.advance() // skip ')'
 
 	return &entities.MongoCommand{
 		Type:       entities.DROP_COLLECTION,
@@ -371,13 +528,16 @@ func (p *MongoParser) parseDrop(collection string) (*entities.MongoCommand, erro
 }
 
 // ✅ NUEVA FUNCIÓN: parseDropDatabase para db.dropDatabase()
-func (p *MongoParser) parseDropDatabase() (*entities.MongoCommand, error) {
+func (p *parseState) parseDropDatabase() (*entities.MongoCommand, error) {
+	p.enter("drop_database", "parseDropDatabase")
+	defer p.leave()
+
 	p.advance() // skip 'dropDatabase'
 
 	if p.current.Type != entities.LEFT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba '(' después de dropDatabase"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedLeftParen, "dropDatabase")},
 		}, nil
 	}
 	p.advance()
@@ -385,7 +545,7 @@ func (p *MongoParser) parseDropDatabase() (*entities.MongoCommand, error) {
 	if p.current.Type != entities.RIGHT_PAREN {
 		return &entities.MongoCommand{
 			IsValid: false,
-			Errors:  []string{"Se esperaba ')' después de dropDatabase"},
+			Errors:  []*entities.Diagnostic{entities.NewDiagnostic(i18n.ParseExpectedRightParen, "dropDatabase")},
 		}, nil
 	}
 	p.advance()
@@ -397,9 +557,12 @@ func (p *MongoParser) parseDropDatabase() (*entities.MongoCommand, error) {
 	}, nil
 }
 
-func (p *MongoParser) parseDocument() (map[string]interface{}, error) {
+func (p *parseState) parseDocument() (map[string]interface{}, error) {
+	p.enter("document", "parseDocument")
+	defer p.leave()
+
 	if p.current.Type != entities.LEFT_BRACE {
-		return nil, fmt.Errorf("se esperaba '{' al inicio del documento")
+		return nil, entities.NewDiagnostic(i18n.ParseExpectedLeftBrace)
 	}
 	p.advance()
 
@@ -407,61 +570,115 @@ func (p *MongoParser) parseDocument() (map[string]interface{}, error) {
 
 	// Documento vacío
 	if p.current.Type == entities.RIGHT_BRACE {
+		p.branch("empty")
 		p.advance()
 		return document, nil
 	}
 
 	for {
-		// Parse key - ✅ MEJORADO: Aceptar tanto strings como identificadores y operadores $
-		var key string
-		
-		if p.current.Type == entities.STRING {
-			key = p.current.Value
-			p.advance()
-		} else if p.current.Type == entities.IDENTIFIER {
-			key = p.current.Value
-			p.advance()
-		} else if p.current.Type == entities.DOLLAR_SIGN {
-			// ✅ NUEVO: Manejar operadores como $set, $inc, etc.
-			p.advance() // skip '$'
-			if p.current.Type != entities.IDENTIFIER {
-				return nil, fmt.Errorf("se esperaba identificador después de '$'")
-			}
-			key = "$" + p.current.Value
+		if err := p.parsePair(document); err != nil {
+			return nil, err
+		}
+
+		if p.current.Type == entities.RIGHT_BRACE {
 			p.advance()
-		} else {
-			return nil, fmt.Errorf("se esperaba string, identificador o operador $ como clave")
+			break
 		}
 
-		if p.current.Type != entities.COLON {
-			return nil, fmt.Errorf("se esperaba ':' después de la clave")
+		if p.current.Type != entities.COMMA {
+			return nil, entities.NewDiagnostic(i18n.ParseExpectedCommaOrRightBrace)
 		}
 		p.advance()
+	}
+
+	return document, nil
+}
+
+func (p *parseState) parseArray() ([]interface{}, error) {
+	p.enter("array", "parseArray")
+	defer p.leave()
+
+	p.advance() // skip '['
+
+	array := []interface{}{}
+
+	// Arreglo vacío
+	if p.current.Type == entities.RIGHT_BRACKET {
+		p.branch("empty")
+		p.advance()
+		return array, nil
+	}
 
-		// Parse value
+	for {
 		value, err := p.parseValue()
 		if err != nil {
 			return nil, err
 		}
+		array = append(array, value)
 
-		document[key] = value
-
-		if p.current.Type == entities.RIGHT_BRACE {
+		if p.current.Type == entities.RIGHT_BRACKET {
 			p.advance()
 			break
 		}
 
 		if p.current.Type != entities.COMMA {
-			return nil, fmt.Errorf("se esperaba ',' o '}' en el documento")
+			return nil, entities.NewDiagnostic(i18n.ParseExpectedCommaOrRightBracket)
 		}
 		p.advance()
 	}
 
-	return document, nil
+	return array, nil
+}
+
+// parsePair analiza un par clave: valor y lo agrega a document.
+func (p *parseState) parsePair(document map[string]interface{}) error {
+	p.enter("pair", "parsePair")
+	defer p.leave()
+
+	// ✅ MEJORADO: Aceptar tanto strings como identificadores y operadores $
+	var key string
+
+	if p.current.Type == entities.STRING {
+		p.branch("STRING")
+		key = p.current.Value
+		p.advance()
+	} else if p.current.Type == entities.IDENTIFIER {
+		p.branch("IDENTIFIER")
+		key = p.current.Value
+		p.advance()
+	} else if p.current.Type == entities.DOLLAR_SIGN {
+		// ✅ NUEVO: Manejar operadores como $set, $inc, etc.
+		p.branch("operator")
+		p.advance() // skip '$'
+		if p.current.Type != entities.IDENTIFIER {
+			return entities.NewDiagnostic(i18n.ParseExpectedIdentifierAfterDollar)
+		}
+		key = "$" + p.current.Value
+		p.advance()
+	} else {
+		return entities.NewDiagnostic(i18n.ParseExpectedKey)
+	}
+
+	if p.current.Type != entities.COLON {
+		return entities.NewDiagnostic(i18n.ParseExpectedColon)
+	}
+	p.advance()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	document[key] = value
+	return nil
 }
 
 // ✅ MEJORADO: parseValue para manejar mejor los tipos
-func (p *MongoParser) parseValue() (interface{}, error) {
+func (p *parseState) parseValue() (interface{}, error) {
+	p.enter("value", "parseValue")
+	defer p.leave()
+
+	p.branch(p.current.Type.String())
 	switch p.current.Type {
 	case entities.STRING:
 		value := p.current.Value
@@ -471,11 +688,28 @@ func (p *MongoParser) parseValue() (interface{}, error) {
 		value := p.current.Value
 		p.advance()
 		if strings.Contains(value, ".") {
-			return strconv.ParseFloat(value, 64)
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, entities.NewDiagnostic(i18n.ParseInvalidNumber, value)
+			}
+			return number, nil
 		}
-		return strconv.Atoi(value)
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, entities.NewDiagnostic(i18n.ParseInvalidNumber, value)
+		}
+		return number, nil
 	case entities.LEFT_BRACE:
 		return p.parseDocument()
+	case entities.LEFT_BRACKET:
+		return p.parseArray()
+	case entities.BOOLEAN:
+		value := p.current.Value == "true"
+		p.advance()
+		return value, nil
+	case entities.NULL:
+		p.advance()
+		return nil, nil
 	case entities.IDENTIFIER:
 		// ✅ NUEVO: Permitir identificadores como valores (para campos sin comillas)
 		value := p.current.Value
@@ -485,19 +719,74 @@ func (p *MongoParser) parseValue() (interface{}, error) {
 		// ✅ MEJORADO: Manejar operadores $ como valores
 		p.advance()
 		if p.current.Type != entities.IDENTIFIER {
-			return nil, fmt.Errorf("se esperaba identificador después de '$'")
+			return nil, entities.NewDiagnostic(i18n.ParseExpectedIdentifierAfterDollar)
 		}
 		operator := "$" + p.current.Value
 		p.advance()
 		return operator, nil
 	default:
-		return nil, fmt.Errorf("valor no válido: %s", p.current.Value)
+		return nil, entities.NewDiagnostic(i18n.ParseInvalidValue, p.current.Value)
 	}
 }
 
-func (p *MongoParser) advance() {
+// enter abre un nodo para la producción name, analizada por el método
+// function, bajo la producción actual.
+func (p *parseState) enter(name, function string) {
+	node := &entities.ASTNode{Kind: name}
+	parent := p.nodes[len(p.nodes)-1]
+	parent.Children = append(parent.Children, node)
+	p.nodes = append(p.nodes, node)
+	p.functions = append(p.functions, function)
+
+	p.record(entities.DERIVATION_ENTER, nil, "")
+}
+
+func (p *parseState) leave() {
+	p.record(entities.DERIVATION_LEAVE, nil, "")
+
+	p.nodes = p.nodes[:len(p.nodes)-1]
+	p.functions = p.functions[:len(p.functions)-1]
+}
+
+// branch registra la alternativa elegida a partir del token actual.
+func (p *parseState) branch(alternative string) {
+	p.record(entities.DERIVATION_BRANCH, p.current, alternative)
+}
+
+func (p *parseState) record(action entities.DerivationAction, token *entities.Token, branch string) {
+	if p.derivation == nil {
+		return
+	}
+	depth := len(p.nodes) - 1
+	p.derivation.Record(entities.DerivationStep{
+		Action:     action,
+		Production: p.nodes[depth].Kind,
+		Function:   p.functions[depth],
+		Depth:      depth - 1, // sin contar holder
+		Token:      token,
+		Branch:     branch,
+	})
+}
+
+// advance consume el token actual, que queda como hoja de la producción
+// abierta, y pasa al siguiente.
+func (p *parseState) advance() {
+	if p.current.Type != entities.EOF {
+		parent := p.nodes[len(p.nodes)-1]
+		parent.Children = append(parent.Children, &entities.ASTNode{Kind: p.current.Type.String(), Token: p.current})
+		p.record(entities.DERIVATION_CONSUME, p.current, "")
+	}
+
 	if p.position < len(p.tokens)-1 {
 		p.position++
 		p.current = p.tokens[p.position]
 	}
-}
\ No newline at end of file
+}
+// asDiagnostic conserva los diagnósticos tal cual y envuelve cualquier otro
+// error como valor no válido.
+func asDiagnostic(err error) *entities.Diagnostic {
+	if diagnostic, ok := err.(*entities.Diagnostic); ok {
+		return diagnostic
+	}
+	return entities.NewDiagnostic(i18n.ParseInvalidValue, err)
+}
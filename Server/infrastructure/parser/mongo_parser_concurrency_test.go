@@ -0,0 +1,99 @@
+package parser_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/grammar"
+	"mongo-analyzer/infrastructure/lexer"
+	"mongo-analyzer/infrastructure/parser"
+)
+
+// TestSharedLexerAndParserAreConcurrencySafe analiza muchas oraciones a la
+// vez con una sola instancia de lexer y parser, como hace main.go con las
+// peticiones HTTP, y comprueba que cada resultado coincide con el del
+// análisis secuencial. Ejecútese con -race.
+func TestSharedLexerAndParserAreConcurrencySafe(t *testing.T) {
+	const (
+		sentences  = 200
+		goroutines = 16
+		rounds     = 5
+	)
+
+	generator := grammar.NewGenerator(grammar.MongoGrammar(), 3, 6)
+	l := lexer.NewMongoLexer()
+	p := parser.NewMongoParser()
+
+	inputs := make([]string, sentences)
+	expected := make([]string, sentences)
+	for i := range inputs {
+		inputs[i] = generator.Sentence()
+		summary, err := analyze(l, p, inputs[i])
+		if err != nil {
+			t.Fatalf("error en %q: %v", inputs[i], err)
+		}
+		expected[i] = summary
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				for i := range inputs {
+					index := (i + offset*7) % sentences
+					summary, err := analyze(l, p, inputs[index])
+					if err != nil {
+						errs <- fmt.Errorf("error en %q: %v", inputs[index], err)
+						return
+					}
+					if summary != expected[index] {
+						errs <- fmt.Errorf("resultado distinto para %q:\n%s\nse esperaba:\n%s", inputs[index], summary, expected[index])
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// analyze resume el comando y su árbol en una cadena comparable.
+func analyze(l *lexer.MongoLexer, p *parser.MongoParser, input string) (string, error) {
+	tokens, err := l.Tokenize(input)
+	if err != nil {
+		return "", err
+	}
+	command, err := p.Parse(context.Background(), tokens)
+	if err != nil {
+		return "", err
+	}
+
+	var leaves []string
+	var visit func(node *entities.ASTNode)
+	visit = func(node *entities.ASTNode) {
+		if node.IsLeaf() {
+			if node.Token != nil {
+				leaves = append(leaves, fmt.Sprintf("%s@%d", node.Token.Value, node.Token.Position))
+			}
+			return
+		}
+		for _, child := range node.Children {
+			visit(child)
+		}
+	}
+	visit(command.AST)
+
+	return fmt.Sprintf("%v %s %s %v %v %v %v %v", command.Type, command.Database, command.Collection,
+		command.Document, command.Filter, command.Update, command.UpdatePipeline, leaves), nil
+}
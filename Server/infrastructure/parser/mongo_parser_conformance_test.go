@@ -0,0 +1,126 @@
+package parser_test
+
+import (
+	"context"
+	"testing"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/grammar"
+	"mongo-analyzer/infrastructure/lexer"
+	"mongo-analyzer/infrastructure/parser"
+)
+
+// TestParserAcceptsGrammarSentences genera oraciones aleatorias a partir de
+// la gramática publicada en /grammar y comprueba que MongoParser las acepta
+// y consume todos sus tokens.
+func TestParserAcceptsGrammarSentences(t *testing.T) {
+	const sentences = 2000
+
+	generator := grammar.NewGenerator(grammar.MongoGrammar(), 1, 6)
+	l := lexer.NewMongoLexer()
+	p := parser.NewMongoParser()
+
+	for i := 0; i < sentences; i++ {
+		sentence := generator.Sentence()
+
+		tokens, err := l.Tokenize(sentence)
+		if err != nil {
+			t.Fatalf("error léxico en %q: %v", sentence, err)
+		}
+
+		command, err := p.Parse(context.Background(), tokens)
+		if err != nil {
+			t.Fatalf("error sintáctico en %q: %v", sentence, err)
+		}
+		if !command.IsValid {
+			t.Fatalf("comando inválido %q: %v", sentence, command.Errors)
+		}
+
+		if consumed := countLeaves(command.AST); consumed != len(tokens)-1 {
+			t.Fatalf("el parser consumió %d de %d tokens en %q", consumed, len(tokens)-1, sentence)
+		}
+	}
+}
+
+// TestParserRejectsTrailingTokens comprueba el otro lado de la
+// conformidad: una oración de la gramática seguida de cualquier token ya no
+// es una oración, y el parser debe rechazarla en lugar de ejecutar solo la
+// primera parte, conservando el árbol de lo ya analizado. (Detrás de use sí
+// puede ir db.dropDatabase(): está en la gramática.)
+func TestParserRejectsTrailingTokens(t *testing.T) {
+	suffixes := []string{"garbage", "db", "db.alumnos.drop()", "use escuela", "{}", ")", "1"}
+
+	generator := grammar.NewGenerator(grammar.MongoGrammar(), 3, 6)
+	l := lexer.NewMongoLexer()
+	p := parser.NewMongoParser()
+
+	inputs := []string{
+		`db.alumnos.find({}) db.dropDatabase()`,
+		`db.alumnos.find({edad: {$gt: 1}}) garbage`,
+		`use escuela db`,
+		`use escuela db.alumnos`,
+	}
+	for i := 0; i < 200; i++ {
+		inputs = append(inputs, generator.Sentence()+" "+suffixes[i%len(suffixes)])
+	}
+
+	for _, sentence := range inputs {
+
+		tokens, err := l.Tokenize(sentence)
+		if err != nil {
+			t.Fatalf("error léxico en %q: %v", sentence, err)
+		}
+		command, err := p.Parse(context.Background(), tokens)
+		if err == nil && command.IsValid {
+			t.Fatalf("el parser aceptó %q", sentence)
+		}
+		if err == nil && command.AST == nil {
+			t.Fatalf("el parser no conservó el árbol parcial de %q", sentence)
+		}
+	}
+}
+
+// TestGrammarCoversParserProductions comprueba que cada nodo que construye
+// el parser tiene su producción en la gramática.
+func TestGrammarCoversParserProductions(t *testing.T) {
+	spec := grammar.MongoGrammar()
+	generator := grammar.NewGenerator(spec, 2, 6)
+	l := lexer.NewMongoLexer()
+	p := parser.NewMongoParser()
+
+	for i := 0; i < 200; i++ {
+		tokens, err := l.Tokenize(generator.Sentence())
+		if err != nil {
+			t.Fatal(err)
+		}
+		command, err := p.Parse(context.Background(), tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var check func(node *entities.ASTNode)
+		check = func(node *entities.ASTNode) {
+			if node.IsLeaf() {
+				return
+			}
+			if _, ok := spec.Production(node.Kind); !ok {
+				t.Fatalf("el parser construye el nodo %q que no está en la gramática", node.Kind)
+			}
+			for _, child := range node.Children {
+				check(child)
+			}
+		}
+		check(command.AST)
+	}
+}
+
+func countLeaves(node *entities.ASTNode) int {
+	if node.IsLeaf() {
+		return 1
+	}
+	count := 0
+	for _, child := range node.Children {
+		count += countLeaves(child)
+	}
+	return count
+}
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// DefaultProtectedDatabases son las bases de datos internas de MongoDB.
+var DefaultProtectedDatabases = []string{"admin", "config", "local"}
+
+// confirmationTTL es el tiempo que un token de confirmación sigue siendo
+// válido.
+const confirmationTTL = 2 * time.Minute
+
+type pendingConfirmation struct {
+	fingerprint string
+	owner       string
+	expiresAt   time.Time
+}
+
+// GuardrailPolicy protege frente a comandos destructivos: nunca permite
+// eliminar las bases de datos o colecciones protegidas y exige que el resto
+// se confirme reenviando, desde la misma sesión y con la misma API key, el
+// token devuelto por la primera petición.
+type GuardrailPolicy struct {
+	protectedDatabases   []string
+	protectedCollections []string
+	ttl                  time.Duration
+	now                  func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+// NewGuardrailPolicy crea la política. Los nombres protegidos admiten
+// comodines (prod_*) y se comparan sin distinguir mayúsculas; las colecciones
+// pueden indicarse como "coleccion" o "base.coleccion".
+func NewGuardrailPolicy(protectedDatabases, protectedCollections []string) *GuardrailPolicy {
+	return &GuardrailPolicy{
+		protectedDatabases:   protectedDatabases,
+		protectedCollections: protectedCollections,
+		ttl:                  confirmationTTL,
+		now:                  time.Now,
+		pending:              make(map[string]pendingConfirmation),
+	}
+}
+
+func (p *GuardrailPolicy) Authorize(ctx context.Context, command *entities.MongoCommand, confirmation string) *entities.PolicyDecision {
+	decision := &entities.PolicyDecision{Class: entities.ClassOf(command.Type)}
+	if decision.Class != entities.CLASS_DESTRUCTIVE {
+		decision.Allowed = true
+		return decision
+	}
+
+	if reason := p.protectionReason(command); reason != nil {
+		decision.Reason = reason
+		return decision
+	}
+
+	fingerprint := commandFingerprint(command)
+	owner := confirmationOwner(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.purgeExpired()
+
+	if confirmation != "" {
+		// Un token solo se usa una vez, y solo lo puede usar quien lo
+		// recibió: el intento de otro cliente no lo consume
+		pending, ok := p.pending[confirmation]
+		if ok && pending.owner == owner {
+			delete(p.pending, confirmation)
+			if pending.fingerprint == fingerprint {
+				decision.Allowed = true
+				return decision
+			}
+		}
+		decision.Reason = entities.NewDiagnostic(i18n.PolicyInvalidConfirmation)
+	} else {
+		decision.Reason = entities.NewDiagnostic(i18n.PolicyConfirmationRequired, describe(command))
+	}
+
+	token, err := newToken()
+	if err != nil {
+		decision.Reason = entities.NewDiagnostic(i18n.PolicyTokenError, err)
+		return decision
+	}
+	decision.ConfirmationToken = token
+	decision.ExpiresAt = p.now().Add(p.ttl)
+	p.pending[token] = pendingConfirmation{fingerprint: fingerprint, owner: owner, expiresAt: decision.ExpiresAt}
+	return decision
+}
+
+// protectionReason indica por qué el comando afecta a un espacio de nombres
+// protegido, o nil si no lo hace.
+func (p *GuardrailPolicy) protectionReason(command *entities.MongoCommand) *entities.Diagnostic {
+	if matchesAny(p.protectedDatabases, command.Database) {
+		return entities.NewDiagnostic(i18n.PolicyProtectedDatabase, command.Database)
+	}
+
+	if command.Type == entities.DROP_COLLECTION {
+		namespace := command.Database + "." + command.Collection
+		if matchesAny(p.protectedCollections, command.Collection) || matchesAny(p.protectedCollections, namespace) {
+			return entities.NewDiagnostic(i18n.PolicyProtectedCollection, command.Collection)
+		}
+	}
+
+	return nil
+}
+
+func (p *GuardrailPolicy) purgeExpired() {
+	now := p.now()
+	for token, pending := range p.pending {
+		if now.After(pending.expiresAt) {
+			delete(p.pending, token)
+		}
+	}
+}
+
+// commandFingerprint identifica el comando confirmado, de modo que un token
+// no sirva para otro comando.
+func commandFingerprint(command *entities.MongoCommand) string {
+	return fmt.Sprintf("%d|%s|%s", command.Type, command.Database, command.Collection)
+}
+
+// confirmationOwner identifica al cliente que recibe el token: la sesión y,
+// si la petición trae API key, su principal.
+func confirmationOwner(ctx context.Context) string {
+	var session, principal string
+	if current := entities.SessionFromContext(ctx); current != nil {
+		session = current.ID
+	}
+	if current := entities.PrincipalFromContext(ctx); current != nil {
+		principal = current.Name
+	}
+	return session + "|" + principal
+}
+
+func describe(command *entities.MongoCommand) string {
+	if command.Type == entities.DROP_COLLECTION {
+		return "db." + command.Collection + ".drop()"
+	}
+	return "db.dropDatabase() (" + command.Database + ")"
+}
+
+func matchesAny(patterns []string, name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func newToken() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,138 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+func withSession(id string) context.Context {
+	return entities.ContextWithSession(context.Background(), entities.NewSession(id, time.Now()))
+}
+
+func dropCollection(database, collection string) *entities.MongoCommand {
+	return &entities.MongoCommand{Type: entities.DROP_COLLECTION, Database: database, Collection: collection, IsValid: true}
+}
+
+// reasonCode devuelve el código del motivo de la decisión, o "".
+func reasonCode(decision *entities.PolicyDecision) string {
+	if decision.Reason == nil {
+		return ""
+	}
+	return decision.Reason.Code
+}
+
+func TestGuardrailProtectedNamesIgnoreCase(t *testing.T) {
+	policy := NewGuardrailPolicy([]string{"admin", "prod_*"}, []string{"auditoria", "escuela.notas"})
+
+	cases := []struct {
+		command *entities.MongoCommand
+		code    string
+	}{
+		{&entities.MongoCommand{Type: entities.DROP_DATABASE, Database: "ADMIN"}, i18n.PolicyProtectedDatabase},
+		{&entities.MongoCommand{Type: entities.DROP_DATABASE, Database: "Prod_Ventas"}, i18n.PolicyProtectedDatabase},
+		{&entities.MongoCommand{Type: entities.DROP_DATABASE, Database: "escuela"}, i18n.PolicyConfirmationRequired},
+		{dropCollection("prod_ventas", "pedidos"), i18n.PolicyProtectedDatabase},
+		{dropCollection("escuela", "AUDITORIA"), i18n.PolicyProtectedCollection},
+		{dropCollection("Escuela", "Notas"), i18n.PolicyProtectedCollection},
+		{dropCollection("otra", "notas"), i18n.PolicyConfirmationRequired},
+		{&entities.MongoCommand{Type: entities.FIND, Database: "admin", Collection: "system.users"}, ""},
+	}
+	for _, tc := range cases {
+		decision := policy.Authorize(withSession("a"), tc.command, "")
+		if code := reasonCode(decision); code != tc.code {
+			t.Errorf("%v %s.%s: motivo %q, se esperaba %q", tc.command.Type, tc.command.Database, tc.command.Collection, code, tc.code)
+		}
+		if protected := tc.code == i18n.PolicyProtectedDatabase || tc.code == i18n.PolicyProtectedCollection; protected && decision.ConfirmationToken != "" {
+			t.Errorf("%s.%s: se emitió un token para un espacio de nombres protegido", tc.command.Database, tc.command.Collection)
+		}
+	}
+}
+
+func TestGuardrailConfirmationTokens(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	alumnos := dropCollection("escuela", "alumnos")
+
+	cases := []struct {
+		name string
+		// confirm reenvía el token y devuelve la decisión
+		confirm func(policy *GuardrailPolicy, token string) *entities.PolicyDecision
+		allowed bool
+	}{
+		{"misma sesión y comando", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			return policy.Authorize(withSession("a"), alumnos, token)
+		}, true},
+		{"token ya usado", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			policy.Authorize(withSession("a"), alumnos, token)
+			return policy.Authorize(withSession("a"), alumnos, token)
+		}, false},
+		{"token caducado", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			policy.now = func() time.Time { return now.Add(confirmationTTL + time.Second) }
+			return policy.Authorize(withSession("a"), alumnos, token)
+		}, false},
+		{"otro comando", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			return policy.Authorize(withSession("a"), dropCollection("escuela", "notas"), token)
+		}, false},
+		{"otra sesión", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			return policy.Authorize(withSession("b"), alumnos, token)
+		}, false},
+		{"otra API key", func(policy *GuardrailPolicy, token string) *entities.PolicyDecision {
+			ctx := entities.ContextWithPrincipal(withSession("a"), &entities.Principal{Name: "ci", Role: "admin"})
+			return policy.Authorize(ctx, alumnos, token)
+		}, false},
+	}
+	for _, tc := range cases {
+		policy := NewGuardrailPolicy(nil, nil)
+		policy.now = func() time.Time { return now }
+
+		first := policy.Authorize(withSession("a"), alumnos, "")
+		if first.Allowed || first.ConfirmationToken == "" {
+			t.Fatalf("%s: la primera petición no pidió confirmación", tc.name)
+		}
+
+		decision := tc.confirm(policy, first.ConfirmationToken)
+		if decision.Allowed != tc.allowed {
+			t.Errorf("%s: permitido = %v, se esperaba %v", tc.name, decision.Allowed, tc.allowed)
+		}
+		if !tc.allowed && (reasonCode(decision) != i18n.PolicyInvalidConfirmation || decision.ConfirmationToken == "") {
+			t.Errorf("%s: motivo %q, token %q", tc.name, reasonCode(decision), decision.ConfirmationToken)
+		}
+	}
+}
+
+// El intento de otro cliente no consume el token de quien lo recibió.
+func TestGuardrailForeignConfirmationKeepsToken(t *testing.T) {
+	policy := NewGuardrailPolicy(nil, nil)
+	alumnos := dropCollection("escuela", "alumnos")
+
+	token := policy.Authorize(withSession("a"), alumnos, "").ConfirmationToken
+	policy.Authorize(withSession("b"), alumnos, token)
+
+	if decision := policy.Authorize(withSession("a"), alumnos, token); !decision.Allowed {
+		t.Fatalf("el token dejó de servir tras el intento de otra sesión: %q", reasonCode(decision))
+	}
+}
+
+func TestGuardrailPurgeExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := NewGuardrailPolicy(nil, nil)
+	policy.now = func() time.Time { return now }
+
+	policy.Authorize(withSession("a"), dropCollection("escuela", "alumnos"), "")
+	now = now.Add(confirmationTTL / 2)
+	recent := policy.Authorize(withSession("a"), dropCollection("escuela", "notas"), "").ConfirmationToken
+
+	now = now.Add(confirmationTTL/2 + time.Second)
+	policy.mu.Lock()
+	policy.purgeExpired()
+	remaining := len(policy.pending)
+	_, kept := policy.pending[recent]
+	policy.mu.Unlock()
+
+	if remaining != 1 || !kept {
+		t.Fatalf("quedan %d tokens (el reciente se conserva: %v), se esperaba solo el reciente", remaining, kept)
+	}
+}
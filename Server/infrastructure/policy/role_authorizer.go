@@ -0,0 +1,198 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// Role define qué comandos puede ejecutar una API key y sobre qué espacios
+// de nombres. Los patrones tienen la forma "base" o "base.coleccion" y
+// admiten comodines (shop_*.*); "base" equivale a "base.*".
+type Role struct {
+	Commands   []entities.CommandType
+	Namespaces []string
+}
+
+// DefaultRoles son los roles disponibles si el archivo de claves no los
+// redefine.
+var DefaultRoles = map[string]Role{
+	"reader": {
+		Commands:   []entities.CommandType{entities.USE_DATABASE, entities.FIND},
+		Namespaces: []string{"*"},
+	},
+	"writer": {
+		Commands: []entities.CommandType{
+			entities.USE_DATABASE, entities.FIND, entities.CREATE_COLLECTION,
+			entities.INSERT_ONE, entities.UPDATE_ONE, entities.REPLACE_ONE, entities.DELETE_ONE,
+		},
+		Namespaces: []string{"*"},
+	},
+	"admin": {
+		Commands:   entities.AllCommandTypes(),
+		Namespaces: []string{"*"},
+	},
+}
+
+// RoleAuthorizer autoriza los comandos según el rol asociado a la API key de
+// la petición.
+type RoleAuthorizer struct {
+	roles map[string]Role
+	keys  map[string]*entities.Principal
+}
+
+type keyFile struct {
+	Roles map[string]struct {
+		Commands   []string `json:"commands"`
+		Namespaces []string `json:"namespaces"`
+	} `json:"roles"`
+	Keys []struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+		Role string `json:"role"`
+	} `json:"keys"`
+}
+
+// LoadRoleAuthorizer lee un archivo JSON con esta forma:
+//
+//	{
+//	  "roles": { "analyst": { "commands": ["USE_DATABASE", "FIND"], "namespaces": ["shop.*"] } },
+//	  "keys":  [ { "name": "ci", "key": "s3cr3t", "role": "analyst" } ]
+//	}
+//
+// Los roles del archivo se añaden a DefaultRoles o los sustituyen.
+func LoadRoleAuthorizer(filename string) (*RoleAuthorizer, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]Role, len(DefaultRoles)+len(file.Roles))
+	for name, role := range DefaultRoles {
+		roles[name] = role
+	}
+	for name, spec := range file.Roles {
+		role := Role{Namespaces: spec.Namespaces}
+		for _, commandName := range spec.Commands {
+			commandType, ok := entities.ParseCommandType(strings.ToUpper(commandName))
+			if !ok {
+				return nil, entities.NewDiagnostic(i18n.AuthUnknownCommand, name, commandName)
+			}
+			role.Commands = append(role.Commands, commandType)
+		}
+		for _, pattern := range spec.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, entities.NewDiagnostic(i18n.AuthInvalidPattern, name, pattern)
+			}
+		}
+		roles[name] = role
+	}
+
+	authorizer := NewRoleAuthorizer(roles)
+	for _, key := range file.Keys {
+		if _, ok := roles[key.Role]; !ok {
+			return nil, entities.NewDiagnostic(i18n.AuthUnknownRole, key.Name, key.Role)
+		}
+		authorizer.AddKey(key.Key, &entities.Principal{Name: key.Name, Role: key.Role})
+	}
+	return authorizer, nil
+}
+
+func NewRoleAuthorizer(roles map[string]Role) *RoleAuthorizer {
+	return &RoleAuthorizer{
+		roles: roles,
+		keys:  make(map[string]*entities.Principal),
+	}
+}
+
+// AddKey asocia una API key a un principal.
+func (a *RoleAuthorizer) AddKey(key string, principal *entities.Principal) {
+	a.keys[key] = principal
+}
+
+// Authenticate devuelve el principal de la API key, o false si no existe.
+func (a *RoleAuthorizer) Authenticate(key string) (*entities.Principal, bool) {
+	if key == "" {
+		return nil, false
+	}
+	principal, ok := a.keys[key]
+	return principal, ok
+}
+
+func (a *RoleAuthorizer) Authorize(ctx context.Context, command *entities.MongoCommand) *entities.AuthorizationError {
+	namespace := command.Database
+	if command.Collection != "" {
+		namespace += "." + command.Collection
+	}
+
+	principal := entities.PrincipalFromContext(ctx)
+	if principal == nil {
+		return &entities.AuthorizationError{
+			Command:   command.Type,
+			Namespace: namespace,
+			Reason:    entities.NewDiagnostic(i18n.AuthMissingPrincipal, command.Type),
+		}
+	}
+
+	role := a.roles[principal.Role]
+	denied := &entities.AuthorizationError{Role: principal.Role, Command: command.Type, Namespace: namespace}
+
+	if !allowsCommand(role, command.Type) {
+		denied.Reason = entities.NewDiagnostic(i18n.AuthCommandNotAllowed, principal.Role, command.Type)
+		return denied
+	}
+	if !allowsNamespace(role, command) {
+		denied.Reason = entities.NewDiagnostic(i18n.AuthNamespaceNotAllowed, principal.Role, namespace)
+		return denied
+	}
+	return nil
+}
+
+func allowsCommand(role Role, commandType entities.CommandType) bool {
+	for _, allowed := range role.Commands {
+		if allowed == commandType {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsNamespace comprueba el espacio de nombres del comando. Cambiar de
+// base de datos basta con acceso a alguna de sus colecciones; eliminarla
+// exige acceso a la base de datos completa.
+func allowsNamespace(role Role, command *entities.MongoCommand) bool {
+	for _, pattern := range role.Namespaces {
+		databasePattern, collectionPattern := pattern, "*"
+		if i := strings.Index(pattern, "."); i >= 0 {
+			databasePattern, collectionPattern = pattern[:i], pattern[i+1:]
+		}
+
+		if matched, _ := path.Match(databasePattern, command.Database); !matched {
+			continue
+		}
+
+		switch {
+		case command.Type == entities.USE_DATABASE:
+			return true
+		case command.Collection == "":
+			if collectionPattern == "*" {
+				return true
+			}
+		default:
+			if matched, _ := path.Match(collectionPattern, command.Collection); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+const keysFile = `{
+	"roles": { "analista": { "commands": ["use_database", "FIND"], "namespaces": ["shop", "escuela.alumnos", "logs_*.*"] } },
+	"keys": [
+		{ "name": "lector", "key": "k-reader", "role": "reader" },
+		{ "name": "escritor", "key": "k-writer", "role": "writer" },
+		{ "name": "admin", "key": "k-admin", "role": "admin" },
+		{ "name": "analista", "key": "k-analyst", "role": "analista" }
+	]
+}`
+
+// loadKeys escribe contents en un archivo temporal y lo carga.
+func loadKeys(t *testing.T, contents string) (*RoleAuthorizer, error) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(filename, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadRoleAuthorizer(filename)
+}
+
+func TestRoleAuthorizerAuthorize(t *testing.T) {
+	authorizer, err := loadKeys(t, keysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		key        string
+		command    entities.CommandType
+		database   string
+		collection string
+		code       string
+	}{
+		{"k-reader", entities.FIND, "escuela", "alumnos", ""},
+		{"k-reader", entities.USE_DATABASE, "cualquiera", "", ""},
+		{"k-reader", entities.INSERT_ONE, "escuela", "alumnos", i18n.AuthCommandNotAllowed},
+		{"k-writer", entities.INSERT_ONE, "escuela", "alumnos", ""},
+		{"k-writer", entities.CREATE_COLLECTION, "escuela", "notas", ""},
+		{"k-writer", entities.DROP_COLLECTION, "escuela", "alumnos", i18n.AuthCommandNotAllowed},
+		{"k-writer", entities.DROP_DATABASE, "escuela", "", i18n.AuthCommandNotAllowed},
+		{"k-admin", entities.DROP_DATABASE, "escuela", "", ""},
+		// "shop" equivale a "shop.*"
+		{"k-analyst", entities.FIND, "shop", "pedidos", ""},
+		{"k-analyst", entities.FIND, "shop", "", ""},
+		{"k-analyst", entities.FIND, "escuela", "alumnos", ""},
+		{"k-analyst", entities.FIND, "escuela", "notas", i18n.AuthNamespaceNotAllowed},
+		// sin colección hace falta acceso a toda la base de datos
+		{"k-analyst", entities.FIND, "escuela", "", i18n.AuthNamespaceNotAllowed},
+		{"k-analyst", entities.FIND, "logs_2026", "accesos", ""},
+		{"k-analyst", entities.FIND, "logs", "accesos", i18n.AuthNamespaceNotAllowed},
+		// use basta con acceso a alguna colección de la base de datos
+		{"k-analyst", entities.USE_DATABASE, "escuela", "", ""},
+		{"k-analyst", entities.USE_DATABASE, "ventas", "", i18n.AuthNamespaceNotAllowed},
+		{"k-analyst", entities.INSERT_ONE, "shop", "pedidos", i18n.AuthCommandNotAllowed},
+		{"", entities.FIND, "escuela", "alumnos", i18n.AuthMissingPrincipal},
+	}
+	for _, tc := range cases {
+		ctx := context.Background()
+		if principal, ok := authorizer.Authenticate(tc.key); ok {
+			ctx = entities.ContextWithPrincipal(ctx, principal)
+		}
+		command := &entities.MongoCommand{Type: tc.command, Database: tc.database, Collection: tc.collection}
+
+		var code string
+		if denied := authorizer.Authorize(ctx, command); denied != nil {
+			code = denied.Reason.Code
+		}
+		if code != tc.code {
+			t.Errorf("%s %v %s.%s: motivo %q, se esperaba %q", tc.key, tc.command, tc.database, tc.collection, code, tc.code)
+		}
+	}
+}
+
+func TestRoleAuthorizerAuthenticate(t *testing.T) {
+	authorizer, err := loadKeys(t, keysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if principal, ok := authorizer.Authenticate("k-analyst"); !ok || principal.Name != "analista" || principal.Role != "analista" {
+		t.Fatalf("k-analyst = %v, %v", principal, ok)
+	}
+	for _, key := range []string{"", "desconocida"} {
+		if _, ok := authorizer.Authenticate(key); ok {
+			t.Errorf("la clave %q no debería autenticarse", key)
+		}
+	}
+}
+
+func TestLoadRoleAuthorizerRejectsMalformedFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		contents string
+		// code es el diagnóstico esperado, o "" si basta con que falle
+		code string
+	}{
+		{"JSON inválido", `{ "keys": [`, ""},
+		{"comando desconocido", `{ "roles": { "r": { "commands": ["DROP_EVERYTHING"], "namespaces": ["*"] } } }`, i18n.AuthUnknownCommand},
+		{"patrón inválido", `{ "roles": { "r": { "commands": ["FIND"], "namespaces": ["shop["] } } }`, i18n.AuthInvalidPattern},
+		{"rol desconocido", `{ "keys": [ { "name": "ci", "key": "k", "role": "auditor" } ] }`, i18n.AuthUnknownRole},
+	}
+	for _, tc := range cases {
+		_, err := loadKeys(t, tc.contents)
+		if err == nil {
+			t.Errorf("%s: el archivo se aceptó", tc.name)
+			continue
+		}
+		if tc.code == "" {
+			continue
+		}
+		if diagnostic, ok := err.(*entities.Diagnostic); !ok || diagnostic.Code != tc.code {
+			t.Errorf("%s: error %v, se esperaba %s", tc.name, err, tc.code)
+		}
+	}
+
+	if _, err := LoadRoleAuthorizer(filepath.Join(t.TempDir(), "no-existe.json")); err == nil {
+		t.Error("un archivo inexistente se aceptó")
+	}
+}
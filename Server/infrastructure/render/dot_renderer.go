@@ -0,0 +1,45 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// DotRenderer genera el árbol en formato Graphviz DOT.
+type DotRenderer struct{}
+
+func NewDotRenderer() *DotRenderer {
+	return &DotRenderer{}
+}
+
+func (r *DotRenderer) ContentType() string {
+	return "text/vnd.graphviz; charset=utf-8"
+}
+
+func (r *DotRenderer) Render(root *entities.ASTNode) (string, error) {
+	var b strings.Builder
+	b.WriteString("digraph ParseTree {\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	walk(root, func(node *entities.ASTNode, id, parent int) {
+		shape := "ellipse"
+		if node.IsLeaf() {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  n%d [label=%s, shape=%s];\n", id, dotQuote(nodeLabel(node)), shape)
+		if parent >= 0 {
+			fmt.Fprintf(&b, "  n%d -> n%d;\n", parent, id)
+		}
+	})
+
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,412 @@
+package render
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExtendedJSONFormat es la forma en que se escriben los valores BSON de un
+// resultado.
+type ExtendedJSONFormat string
+
+const (
+	// ExtendedJSONRelaxed es Extended JSON v2 relajado: los números y las
+	// fechas habituales se escriben como JSON legible.
+	ExtendedJSONRelaxed ExtendedJSONFormat = "relaxed"
+	// ExtendedJSONCanonical es Extended JSON v2 canónico: conserva el tipo
+	// exacto de cada valor ({"$numberInt": "1"}).
+	ExtendedJSONCanonical ExtendedJSONFormat = "canonical"
+	// ExtendedJSONShell escribe los valores como los muestra mongosh
+	// (ObjectId("..."), ISODate("...")). No es JSON.
+	ExtendedJSONShell ExtendedJSONFormat = "shell"
+)
+
+// ParseExtendedJSONFormat interpreta el formato pedido por el cliente. Un
+// valor vacío equivale al formato relajado.
+func ParseExtendedJSONFormat(value string) (ExtendedJSONFormat, bool) {
+	switch ExtendedJSONFormat(value) {
+	case "", ExtendedJSONRelaxed:
+		return ExtendedJSONRelaxed, true
+	case ExtendedJSONCanonical, ExtendedJSONShell:
+		return ExtendedJSONFormat(value), true
+	}
+	return "", false
+}
+
+// shellLineWidth es el ancho a partir del cual el formato shell escribe un
+// documento en varias líneas, como hace mongosh.
+const shellLineWidth = 72
+
+// ExtendedJSONEncoder escribe valores con los tipos del driver de MongoDB
+// (primitive.ObjectID, primitive.DateTime, primitive.Decimal128...) y los
+// que produce el parser. Los documentos bson.D conservan el orden de sus
+// campos; los mapas se escriben con las claves ordenadas.
+type ExtendedJSONEncoder struct {
+	format ExtendedJSONFormat
+}
+
+func NewExtendedJSONEncoder(format ExtendedJSONFormat) *ExtendedJSONEncoder {
+	return &ExtendedJSONEncoder{format: format}
+}
+
+// Encode devuelve el valor escrito en el formato del encoder.
+func (e *ExtendedJSONEncoder) Encode(value interface{}) string {
+	tree := e.convert(value)
+	if e.format == ExtendedJSONShell {
+		return tree.shell(0)
+	}
+	var b strings.Builder
+	tree.json(&b)
+	return b.String()
+}
+
+// extNode es un valor ya convertido: un escalar escrito o un documento o
+// array con sus elementos.
+type extNode struct {
+	scalar   string
+	isDoc    bool
+	isArray  bool
+	keys     []string
+	children []extNode
+}
+
+func scalarNode(text string) extNode {
+	return extNode{scalar: text}
+}
+
+// wrapper es un documento de un solo campo como {"$oid": "..."}.
+func wrapper(key string, value extNode) extNode {
+	return extNode{isDoc: true, keys: []string{key}, children: []extNode{value}}
+}
+
+func documentNode(keys []string, values []extNode) extNode {
+	return extNode{isDoc: true, keys: keys, children: values}
+}
+
+func (e *ExtendedJSONEncoder) shell() bool {
+	return e.format == ExtendedJSONShell
+}
+
+func (e *ExtendedJSONEncoder) convert(value interface{}) extNode {
+	switch typed := value.(type) {
+	case nil, primitive.Null:
+		return scalarNode("null")
+	case bool:
+		return scalarNode(strconv.FormatBool(typed))
+	case string:
+		return scalarNode(quote(typed))
+	case primitive.Symbol:
+		return e.typed("$symbol", quote(string(typed)), quote(string(typed)))
+	case int:
+		if typed >= math.MinInt32 && typed <= math.MaxInt32 {
+			return e.int32(int64(typed))
+		}
+		return e.int64(int64(typed))
+	case int8:
+		return e.int32(int64(typed))
+	case int16:
+		return e.int32(int64(typed))
+	case int32:
+		return e.int32(int64(typed))
+	case int64:
+		return e.int64(typed)
+	case uint8:
+		return e.int32(int64(typed))
+	case uint16:
+		return e.int32(int64(typed))
+	case uint32:
+		return e.int64(int64(typed))
+	case float32:
+		return e.double(float64(typed))
+	case float64:
+		return e.double(typed)
+	case primitive.Decimal128:
+		return e.typed("$numberDecimal", quote(typed.String()), fmt.Sprintf("Decimal128(%s)", quote(typed.String())))
+	case primitive.ObjectID:
+		return e.typed("$oid", quote(typed.Hex()), fmt.Sprintf("ObjectId(%s)", quote(typed.Hex())))
+	case time.Time:
+		return e.date(typed)
+	case primitive.DateTime:
+		return e.date(typed.Time())
+	case primitive.Timestamp:
+		if e.shell() {
+			return scalarNode(fmt.Sprintf("Timestamp({ t: %d, i: %d })", typed.T, typed.I))
+		}
+		return wrapper("$timestamp", documentNode(
+			[]string{"t", "i"},
+			[]extNode{scalarNode(strconv.FormatUint(uint64(typed.T), 10)), scalarNode(strconv.FormatUint(uint64(typed.I), 10))},
+		))
+	case primitive.Binary:
+		return e.binary(typed)
+	case primitive.Regex:
+		options := sortedOptions(typed.Options)
+		if e.shell() {
+			return scalarNode("/" + strings.ReplaceAll(typed.Pattern, "/", `\/`) + "/" + options)
+		}
+		return wrapper("$regularExpression", documentNode(
+			[]string{"pattern", "options"},
+			[]extNode{scalarNode(quote(typed.Pattern)), scalarNode(quote(options))},
+		))
+	case primitive.JavaScript:
+		return e.typed("$code", quote(string(typed)), fmt.Sprintf("Code(%s)", quote(string(typed))))
+	case primitive.MinKey:
+		return e.typed("$minKey", "1", "MinKey()")
+	case primitive.MaxKey:
+		return e.typed("$maxKey", "1", "MaxKey()")
+	case primitive.Undefined:
+		return e.typed("$undefined", "true", "undefined")
+	case primitive.D:
+		keys := make([]string, len(typed))
+		values := make([]extNode, len(typed))
+		for i, element := range typed {
+			keys[i] = element.Key
+			values[i] = e.convert(element.Value)
+		}
+		return documentNode(keys, values)
+	case primitive.M:
+		return e.convertMap(typed)
+	case map[string]interface{}:
+		return e.convertMap(typed)
+	case primitive.A:
+		return e.convertArray(reflect.ValueOf([]interface{}(typed)))
+	case []byte:
+		return e.binary(primitive.Binary{Data: typed})
+	}
+
+	// Slices y mapas de otros tipos ([]bson.M, []map[string]interface{}...)
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		return e.convertArray(reflected)
+	case reflect.Map:
+		if reflected.Type().Key().Kind() == reflect.String {
+			fields := make(map[string]interface{}, reflected.Len())
+			for _, key := range reflected.MapKeys() {
+				fields[key.String()] = reflected.MapIndex(key).Interface()
+			}
+			return e.convertMap(fields)
+		}
+	case reflect.Ptr:
+		if reflected.IsNil() {
+			return scalarNode("null")
+		}
+		return e.convert(reflected.Elem().Interface())
+	}
+
+	// Cualquier otro valor se escribe como lo haría encoding/json
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return scalarNode(quote(fmt.Sprint(value)))
+	}
+	return scalarNode(string(encoded))
+}
+
+func (e *ExtendedJSONEncoder) convertMap(fields map[string]interface{}) extNode {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]extNode, len(keys))
+	for i, key := range keys {
+		values[i] = e.convert(fields[key])
+	}
+	return documentNode(keys, values)
+}
+
+func (e *ExtendedJSONEncoder) convertArray(array reflect.Value) extNode {
+	node := extNode{isArray: true, children: make([]extNode, array.Len())}
+	for i := range node.children {
+		node.children[i] = e.convert(array.Index(i).Interface())
+	}
+	return node
+}
+
+// typed escribe un valor que en JSON necesita un envoltorio
+// ({"$oid": ...}) y en shell un constructor (ObjectId(...)).
+func (e *ExtendedJSONEncoder) typed(key, value, shell string) extNode {
+	if e.shell() {
+		return scalarNode(shell)
+	}
+	return wrapper(key, scalarNode(value))
+}
+
+func (e *ExtendedJSONEncoder) int32(n int64) extNode {
+	if e.format == ExtendedJSONCanonical {
+		return wrapper("$numberInt", scalarNode(quote(strconv.FormatInt(n, 10))))
+	}
+	return scalarNode(strconv.FormatInt(n, 10))
+}
+
+func (e *ExtendedJSONEncoder) int64(n int64) extNode {
+	text := strconv.FormatInt(n, 10)
+	switch e.format {
+	case ExtendedJSONCanonical:
+		return wrapper("$numberLong", scalarNode(quote(text)))
+	case ExtendedJSONShell:
+		return scalarNode(fmt.Sprintf("Long(%s)", quote(text)))
+	}
+	return scalarNode(text)
+}
+
+func (e *ExtendedJSONEncoder) double(f float64) extNode {
+	text := formatDouble(f)
+	finite := !math.IsInf(f, 0) && !math.IsNaN(f)
+	switch {
+	case e.shell():
+		return scalarNode(text)
+	case e.format == ExtendedJSONCanonical || !finite:
+		return wrapper("$numberDouble", scalarNode(quote(text)))
+	}
+	return scalarNode(text)
+}
+
+// formatDouble escribe el número más corto que lo representa, siempre con
+// parte decimal o exponente para distinguirlo de un entero.
+func formatDouble(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
+
+// date escribe una fecha. En formato relajado se usa ISO-8601 si el año
+// está entre 1970 y 9999, como indica la especificación.
+func (e *ExtendedJSONEncoder) date(t time.Time) extNode {
+	t = t.UTC()
+	iso := t.Format("2006-01-02T15:04:05.000Z")
+	switch {
+	case e.shell():
+		return scalarNode(fmt.Sprintf("ISODate(%s)", quote(iso)))
+	case e.format == ExtendedJSONRelaxed && t.Year() >= 1970 && t.Year() <= 9999:
+		return wrapper("$date", scalarNode(quote(iso)))
+	}
+	milliseconds := strconv.FormatInt(t.UnixMilli(), 10)
+	return wrapper("$date", wrapper("$numberLong", scalarNode(quote(milliseconds))))
+}
+
+func (e *ExtendedJSONEncoder) binary(binary primitive.Binary) extNode {
+	encoded := base64.StdEncoding.EncodeToString(binary.Data)
+	if e.shell() {
+		if binary.Subtype == 4 && len(binary.Data) == 16 {
+			return scalarNode(fmt.Sprintf("UUID(%s)", quote(formatUUID(binary.Data))))
+		}
+		return scalarNode(fmt.Sprintf("Binary.createFromBase64(%s, %d)", quote(encoded), binary.Subtype))
+	}
+	return wrapper("$binary", documentNode(
+		[]string{"base64", "subType"},
+		[]extNode{scalarNode(quote(encoded)), scalarNode(quote(fmt.Sprintf("%02x", binary.Subtype)))},
+	))
+}
+
+func formatUUID(data []byte) string {
+	text := hex.EncodeToString(data)
+	return text[0:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+}
+
+// sortedOptions ordena las opciones de una expresión regular, como exige
+// la especificación.
+func sortedOptions(options string) string {
+	letters := strings.Split(options, "")
+	sort.Strings(letters)
+	return strings.Join(letters, "")
+}
+
+// quote escribe una cadena JSON sin escapar <, > y &, como mongosh.
+func quote(value string) string {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (n extNode) json(b *strings.Builder) {
+	switch {
+	case n.isDoc:
+		b.WriteByte('{')
+		for i, key := range n.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(quote(key))
+			b.WriteByte(':')
+			n.children[i].json(b)
+		}
+		b.WriteByte('}')
+	case n.isArray:
+		b.WriteByte('[')
+		for i, child := range n.children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			child.json(b)
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString(n.scalar)
+	}
+}
+
+var shellIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// shell escribe el valor como mongosh: en una línea si cabe y, si no, un
+// elemento por línea con dos espacios de sangría por nivel.
+func (n extNode) shell(depth int) string {
+	if !n.isDoc && !n.isArray {
+		return n.scalar
+	}
+
+	open, close := "{", "}"
+	if n.isArray {
+		open, close = "[", "]"
+	}
+	if len(n.children) == 0 {
+		return open + close
+	}
+
+	items := make([]string, len(n.children))
+	inline := true
+	width := 4
+	for i, child := range n.children {
+		items[i] = child.shell(depth + 1)
+		if n.isDoc {
+			key := n.keys[i]
+			if !shellIdentifier.MatchString(key) {
+				key = quote(key)
+			}
+			items[i] = key + ": " + items[i]
+		}
+		width += len(items[i]) + 2
+		if strings.Contains(items[i], "\n") {
+			inline = false
+		}
+	}
+
+	if inline && depth*2+width <= shellLineWidth {
+		return open + " " + strings.Join(items, ", ") + " " + close
+	}
+	indent := strings.Repeat("  ", depth+1)
+	return open + "\n" + indent + strings.Join(items, ",\n"+indent) + "\n" + strings.Repeat("  ", depth) + close
+}
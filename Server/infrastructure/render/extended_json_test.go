@@ -0,0 +1,43 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExtendedJSONEncoder(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("65a1b2c3d4e5f60718293a4b")
+	created := time.Date(2024, 1, 12, 9, 30, 0, 0, time.UTC)
+	document := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "edad", Value: 20},
+		{Key: "visitas", Value: int64(3)},
+		{Key: "media", Value: 7.0},
+		{Key: "creado", Value: primitive.NewDateTimeFromTime(created)},
+		{Key: "tags", Value: bson.A{"bd", nil}},
+	}
+
+	cases := []struct {
+		format ExtendedJSONFormat
+		want   string
+	}{
+		{ExtendedJSONRelaxed, `{"_id":{"$oid":"65a1b2c3d4e5f60718293a4b"},"edad":20,"visitas":3,"media":7.0,"creado":{"$date":"2024-01-12T09:30:00.000Z"},"tags":["bd",null]}`},
+		{ExtendedJSONCanonical, `{"_id":{"$oid":"65a1b2c3d4e5f60718293a4b"},"edad":{"$numberInt":"20"},"visitas":{"$numberLong":"3"},"media":{"$numberDouble":"7.0"},"creado":{"$date":{"$numberLong":"1705051800000"}},"tags":["bd",null]}`},
+		{ExtendedJSONShell, "{\n" +
+			"  _id: ObjectId(\"65a1b2c3d4e5f60718293a4b\"),\n" +
+			"  edad: 20,\n" +
+			"  visitas: Long(\"3\"),\n" +
+			"  media: 7.0,\n" +
+			"  creado: ISODate(\"2024-01-12T09:30:00.000Z\"),\n" +
+			"  tags: [ \"bd\", null ]\n" +
+			"}"},
+	}
+	for _, c := range cases {
+		if got := NewExtendedJSONEncoder(c.format).Encode(document); got != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.format, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"fmt"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// nodeLabel devuelve el texto de un nodo: el nombre de la producción para
+// los nodos internos y "TIPO lexema" para las hojas.
+func nodeLabel(node *entities.ASTNode) string {
+	if node.IsLeaf() {
+		return fmt.Sprintf("%s %s", node.Token.Type, node.Token.Value)
+	}
+	return node.Kind
+}
+
+// walk recorre el árbol en preorden asignando a cada nodo un identificador
+// estable, y llama a visit con el nodo, su identificador y el de su padre
+// (-1 para la raíz).
+func walk(root *entities.ASTNode, visit func(node *entities.ASTNode, id, parent int)) {
+	next := 0
+	var visitNode func(node *entities.ASTNode, parent int)
+	visitNode = func(node *entities.ASTNode, parent int) {
+		id := next
+		next++
+		visit(node, id, parent)
+		for _, child := range node.Children {
+			visitNode(child, id)
+		}
+	}
+	visitNode(root, -1)
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// MermaidRenderer genera el árbol como diagrama de flujo de Mermaid.
+type MermaidRenderer struct{}
+
+func NewMermaidRenderer() *MermaidRenderer {
+	return &MermaidRenderer{}
+}
+
+func (r *MermaidRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (r *MermaidRenderer) Render(root *entities.ASTNode) (string, error) {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+
+	walk(root, func(node *entities.ASTNode, id, parent int) {
+		label := mermaidQuote(nodeLabel(node))
+		if node.IsLeaf() {
+			fmt.Fprintf(&b, "  n%d[%s]\n", id, label)
+		} else {
+			fmt.Fprintf(&b, "  n%d([%s])\n", id, label)
+		}
+		if parent >= 0 {
+			fmt.Fprintf(&b, "  n%d --> n%d\n", parent, id)
+		}
+	})
+
+	return b.String(), nil
+}
+
+// mermaidQuote usa entidades para las comillas, que Mermaid no permite
+// escapar dentro de una etiqueta entre comillas.
+func mermaidQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,260 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"mongo-analyzer/domain/grammar"
+)
+
+const (
+	rrCharWidth = 7.5
+	rrBoxHeight = 22.0
+	rrBoxPad    = 10.0
+	rrGap       = 12.0
+	rrArc       = 14.0
+	rrMargin    = 20.0
+	rrTitle     = 24.0
+)
+
+// RailroadRenderer dibuja las producciones de una gramática como diagramas
+// de sintaxis (railroad diagrams) en SVG.
+type RailroadRenderer struct{}
+
+func NewRailroadRenderer() *RailroadRenderer {
+	return &RailroadRenderer{}
+}
+
+func (r *RailroadRenderer) ContentType() string {
+	return "image/svg+xml"
+}
+
+// Render dibuja las producciones indicadas, una debajo de otra. Sin nombres
+// se dibujan todas.
+func (r *RailroadRenderer) Render(g *grammar.Grammar, names ...string) (string, error) {
+	productions := g.Productions
+	if len(names) > 0 {
+		productions = nil
+		for _, name := range names {
+			production, ok := g.Production(name)
+			if !ok {
+				return "", fmt.Errorf("producción desconocida: %s", name)
+			}
+			productions = append(productions, production)
+		}
+	}
+
+	var body strings.Builder
+	width, y := 0.0, rrMargin
+	for _, production := range productions {
+		diagram := layoutExpr(production.Expr)
+		w, up, down := diagram.size()
+
+		fmt.Fprintf(&body, `<text x="%.1f" y="%.1f" font-weight="bold">%s</text>`+"\n", rrMargin, y+14, html.EscapeString(production.Name))
+		lineY := y + rrTitle + up
+
+		// Marcas de inicio y fin
+		startX := rrMargin
+		fmt.Fprintf(&body, `<path d="M%.1f %.1fv%.1fm0 %.1fv%.1f" stroke="#333"/>`+"\n", startX, lineY-8, 16.0, -8.0, 0.0)
+		fmt.Fprintf(&body, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#333"/>`+"\n", startX, lineY, startX+rrGap, lineY)
+		diagram.draw(&body, startX+rrGap, lineY)
+		endX := startX + rrGap + w
+		fmt.Fprintf(&body, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#333"/>`+"\n", endX, lineY, endX+rrGap, lineY)
+		fmt.Fprintf(&body, `<path d="M%.1f %.1fv%.1f" stroke="#333"/>`+"\n", endX+rrGap, lineY-8, 16.0)
+
+		if right := endX + rrGap + rrMargin; right > width {
+			width = right
+		}
+		y = lineY + down + rrMargin
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n",
+		width, y, width, y)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+	b.WriteString(body.String())
+	b.WriteString("</svg>\n")
+	return b.String(), nil
+}
+
+// rrElement es un fragmento de diagrama. size devuelve el ancho y cuánto se
+// extiende por encima y por debajo de la línea principal; draw lo dibuja
+// con la línea principal en y empezando en x.
+type rrElement interface {
+	size() (width, up, down float64)
+	draw(b *strings.Builder, x, y float64)
+}
+
+func layoutExpr(expr grammar.Expr) rrElement {
+	switch e := expr.(type) {
+	case grammar.Terminal:
+		return &rrBox{label: e.Text, terminal: true}
+	case grammar.NonTerminal:
+		return &rrBox{label: e.Name}
+	case grammar.Special:
+		return &rrBox{label: e.Description, special: true}
+	case grammar.Sequence:
+		items := make([]rrElement, len(e.Items))
+		for i, item := range e.Items {
+			items[i] = layoutExpr(item)
+		}
+		return &rrSequence{items: items}
+	case grammar.Choice:
+		alternatives := make([]rrElement, len(e.Alternatives))
+		for i, alternative := range e.Alternatives {
+			alternatives[i] = layoutExpr(alternative)
+		}
+		return &rrChoice{alternatives: alternatives}
+	case grammar.Optional:
+		return &rrChoice{alternatives: []rrElement{&rrSkip{}, layoutExpr(e.Item)}}
+	case grammar.Repetition:
+		return &rrChoice{alternatives: []rrElement{&rrSkip{}, &rrLoop{item: layoutExpr(e.Item)}}}
+	}
+	return &rrSkip{}
+}
+
+type rrBox struct {
+	label    string
+	terminal bool
+	special  bool
+}
+
+func (r *rrBox) size() (float64, float64, float64) {
+	return float64(len([]rune(r.label)))*rrCharWidth + 2*rrBoxPad, rrBoxHeight / 2, rrBoxHeight / 2
+}
+
+func (r *rrBox) draw(b *strings.Builder, x, y float64) {
+	w, _, _ := r.size()
+	radius, fill := 0.0, "#e8f0fe"
+	switch {
+	case r.terminal:
+		radius, fill = rrBoxHeight/2, "#fef7e0"
+	case r.special:
+		fill = "#eeeeee"
+	}
+	fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" fill="%s" stroke="#333"/>`+"\n",
+		x, y-rrBoxHeight/2, w, rrBoxHeight, radius, fill)
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+		x+w/2, y, html.EscapeString(r.label))
+}
+
+// rrSkip es el camino vacío de las partes opcionales.
+type rrSkip struct{}
+
+func (r *rrSkip) size() (float64, float64, float64) { return 0, 0, 0 }
+
+func (r *rrSkip) draw(*strings.Builder, float64, float64) {}
+
+type rrSequence struct {
+	items []rrElement
+}
+
+func (r *rrSequence) size() (float64, float64, float64) {
+	width, up, down := 0.0, 0.0, 0.0
+	for i, item := range r.items {
+		w, u, d := item.size()
+		if i > 0 {
+			width += rrGap
+		}
+		width += w
+		up, down = max(up, u), max(down, d)
+	}
+	return width, up, down
+}
+
+func (r *rrSequence) draw(b *strings.Builder, x, y float64) {
+	for i, item := range r.items {
+		if i > 0 {
+			hline(b, x, x+rrGap, y)
+			x += rrGap
+		}
+		item.draw(b, x, y)
+		w, _, _ := item.size()
+		x += w
+	}
+}
+
+// rrChoice dibuja la primera alternativa sobre la línea principal y las
+// demás apiladas debajo.
+type rrChoice struct {
+	alternatives []rrElement
+}
+
+func (r *rrChoice) size() (float64, float64, float64) {
+	inner := 0.0
+	for _, alternative := range r.alternatives {
+		w, _, _ := alternative.size()
+		inner = max(inner, w)
+	}
+	_, down := r.offsets()
+	_, up, _ := r.alternatives[0].size()
+	return inner + 4*rrArc, up, down
+}
+
+// offsets calcula la distancia de cada alternativa a la línea principal y
+// cuánto se extiende la elección por debajo de ella. Cada alternativa queda
+// al menos a 2*rrArc de la anterior para que quepan las curvas.
+func (r *rrChoice) offsets() ([]float64, float64) {
+	offsets := make([]float64, len(r.alternatives))
+	_, _, bottom := r.alternatives[0].size()
+	for i := 1; i < len(r.alternatives); i++ {
+		_, u, d := r.alternatives[i].size()
+		offsets[i] = max(bottom+rrGap+u, offsets[i-1]+2*rrArc)
+		bottom = offsets[i] + d
+	}
+	return offsets, bottom
+}
+
+func (r *rrChoice) draw(b *strings.Builder, x, y float64) {
+	width, _, _ := r.size()
+	left, right := x+2*rrArc, x+width-2*rrArc
+	offsets, _ := r.offsets()
+
+	for i, alternative := range r.alternatives {
+		w, _, _ := alternative.size()
+		branchY := y + offsets[i]
+		if i > 0 {
+			// Bajada desde la línea principal y subida de vuelta
+			fmt.Fprintf(b, `<path d="M%.1f %.1fq%.1f 0 %.1f %.1fv%.1fq0 %.1f %.1f %.1f" fill="none" stroke="#333"/>`+"\n",
+				x, y, rrArc, rrArc, rrArc, offsets[i]-2*rrArc, rrArc, rrArc, rrArc)
+			fmt.Fprintf(b, `<path d="M%.1f %.1fq%.1f 0 %.1f %.1fv%.1fq0 %.1f %.1f %.1f" fill="none" stroke="#333"/>`+"\n",
+				x+width, y, -rrArc, -rrArc, rrArc, offsets[i]-2*rrArc, rrArc, -rrArc, rrArc)
+		} else {
+			hline(b, x, left, y)
+			hline(b, right, x+width, y)
+		}
+		alternative.draw(b, left, branchY)
+		hline(b, left+w, right, branchY)
+	}
+}
+
+// rrLoop es una o más repeticiones: el elemento va en la línea principal y
+// el camino de vuelta por debajo.
+type rrLoop struct {
+	item rrElement
+}
+
+func (r *rrLoop) size() (float64, float64, float64) {
+	w, u, d := r.item.size()
+	return w + 2*rrArc, u, d + rrGap
+}
+
+func (r *rrLoop) draw(b *strings.Builder, x, y float64) {
+	w, _, d := r.item.size()
+	hline(b, x, x+rrArc, y)
+	r.item.draw(b, x+rrArc, y)
+	hline(b, x+rrArc+w, x+2*rrArc+w, y)
+
+	bottom := y + d + rrGap
+	fmt.Fprintf(b, `<path d="M%.1f %.1fq%.1f 0 %.1f %.1fv%.1fq0 %.1f %.1f %.1fH%.1fq%.1f 0 %.1f %.1fv%.1fq0 %.1f %.1f %.1f" fill="none" stroke="#333"/>`+"\n",
+		x+rrArc+w, y, rrArc/2, rrArc/2, rrArc/2, bottom-y-rrArc, rrArc/2, -rrArc/2, rrArc/2,
+		x+rrArc, -rrArc/2, -rrArc/2, -rrArc/2, -(bottom - y - rrArc), -rrArc/2, rrArc/2, -rrArc/2)
+}
+
+func hline(b *strings.Builder, x1, x2, y float64) {
+	if x2 <= x1 {
+		return
+	}
+	fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#333"/>`+"\n", x1, y, x2, y)
+}
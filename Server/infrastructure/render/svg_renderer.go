@@ -0,0 +1,129 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+)
+
+const (
+	svgCharWidth   = 7.5
+	svgNodeHeight  = 28.0
+	svgNodePadding = 12.0
+	svgLevelGap    = 64.0
+	svgSiblingGap  = 14.0
+	svgMargin      = 20.0
+)
+
+// SVGRenderer dibuja el árbol como SVG sin depender de Graphviz. Las hojas
+// se colocan de izquierda a derecha en el orden de la entrada y cada nodo
+// interno se centra sobre sus hijos.
+type SVGRenderer struct{}
+
+func NewSVGRenderer() *SVGRenderer {
+	return &SVGRenderer{}
+}
+
+func (r *SVGRenderer) ContentType() string {
+	return "image/svg+xml"
+}
+
+type svgBox struct {
+	node     *entities.ASTNode
+	label    string
+	x, y     float64 // centro
+	width    float64
+	children []*svgBox
+}
+
+func (r *SVGRenderer) Render(root *entities.ASTNode) (string, error) {
+	nextX := svgMargin
+	maxDepth := 0
+
+	var layout func(node *entities.ASTNode, depth int) *svgBox
+	layout = func(node *entities.ASTNode, depth int) *svgBox {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		label := nodeLabel(node)
+		box := &svgBox{
+			node:  node,
+			label: label,
+			y:     svgMargin + float64(depth)*svgLevelGap + svgNodeHeight/2,
+			width: float64(len([]rune(label)))*svgCharWidth + 2*svgNodePadding,
+		}
+
+		if len(node.Children) == 0 {
+			box.x = nextX + box.width/2
+			nextX += box.width + svgSiblingGap
+			return box
+		}
+
+		start := nextX
+		for _, child := range node.Children {
+			box.children = append(box.children, layout(child, depth+1))
+		}
+		first, last := box.children[0], box.children[len(box.children)-1]
+		box.x = (first.x + last.x) / 2
+
+		// Si el padre es más ancho que sus hijos, se desplaza el subárbol
+		// para que no invada al hermano anterior y se reserva el espacio que
+		// falta para el siguiente.
+		if overlap := start - (box.x - box.width/2); overlap > 0 {
+			shift(box, overlap)
+			nextX += overlap
+		}
+		if right := box.x + box.width/2 + svgSiblingGap; right > nextX {
+			nextX = right
+		}
+		return box
+	}
+
+	tree := layout(root, 0)
+	width := nextX - svgSiblingGap + svgMargin
+	height := 2*svgMargin + float64(maxDepth)*svgLevelGap + svgNodeHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	b.WriteString(`<rect width="100%" height="100%" fill="white"/>` + "\n")
+
+	var drawEdges func(box *svgBox)
+	drawEdges = func(box *svgBox) {
+		for _, child := range box.children {
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#888"/>`+"\n",
+				box.x, box.y+svgNodeHeight/2, child.x, child.y-svgNodeHeight/2)
+			drawEdges(child)
+		}
+	}
+
+	var drawNodes func(box *svgBox)
+	drawNodes = func(box *svgBox) {
+		fill, radius := "#e8f0fe", svgNodeHeight/2
+		if box.node.IsLeaf() {
+			fill, radius = "#fef7e0", 4
+		}
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" fill="%s" stroke="#444"/>`+"\n",
+			box.x-box.width/2, box.y-svgNodeHeight/2, box.width, svgNodeHeight, radius, fill)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+			box.x, box.y, html.EscapeString(box.label))
+		for _, child := range box.children {
+			drawNodes(child)
+		}
+	}
+
+	drawEdges(tree)
+	drawNodes(tree)
+	b.WriteString("</svg>\n")
+
+	return b.String(), nil
+}
+
+func shift(box *svgBox, dx float64) {
+	box.x += dx
+	for _, child := range box.children {
+		shift(child, dx)
+	}
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+)
+
+// DefaultIdleTimeout es el tiempo sin uso tras el que caduca una sesión.
+const DefaultIdleTimeout = 30 * time.Minute
+
+// Store guarda las sesiones en memoria. Las sesiones caducadas se eliminan
+// al acceder al almacén, de modo que no hace falta una goroutine aparte.
+type Store struct {
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*entities.Session
+}
+
+func NewStore(idleTimeout time.Duration) *Store {
+	return &Store{
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		sessions:    make(map[string]*entities.Session),
+	}
+}
+
+// Get devuelve la sesión id si existe y no ha caducado, y la marca como
+// usada.
+func (s *Store) Get(id string) (*entities.Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+
+	session, ok := s.sessions[id]
+	if ok {
+		session.Touch(s.now())
+	}
+	return session, ok
+}
+
+// Create abre una sesión con un identificador aleatorio.
+func (s *Store) Create() (*entities.Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+
+	session := entities.NewSession(id, s.now())
+	s.sessions[id] = session
+	return session, nil
+}
+
+// Delete cierra la sesión id.
+func (s *Store) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	return ok
+}
+
+func (s *Store) purgeExpired() {
+	limit := s.now().Add(-s.idleTimeout)
+	for id, session := range s.sessions {
+		if session.IdleSince(limit) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func newSessionID() (string, error) {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
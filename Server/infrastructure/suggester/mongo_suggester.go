@@ -0,0 +1,186 @@
+package suggester
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/interfaces"
+)
+
+type MongoSuggester struct {
+	catalog   interfaces.NamespaceCatalog
+	schemas   interfaces.SchemaInferrer
+	ttl       time.Duration
+	now       func() time.Time
+	operators []string
+
+	mu          sync.Mutex
+	collections map[string]cachedNames
+}
+
+// cachedNames son las colecciones de una base de datos y el momento en que
+// se consultaron.
+type cachedNames struct {
+	names     []string
+	fetchedAt time.Time
+}
+
+// NewMongoSuggester crea el motor de sugerencias. Las colecciones de cada
+// base de datos se consultan al catálogo una vez cada ttl; los campos salen
+// del esquema inferido, que ya tiene su propia caché. catalog y schemas
+// pueden ser nil; en ese caso no se sugieren colecciones o campos.
+func NewMongoSuggester(catalog interfaces.NamespaceCatalog, schemas interfaces.SchemaInferrer, ttl time.Duration) *MongoSuggester {
+	var operators []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{entities.QueryOperators, entities.UpdateOperators, entities.AggregationOperators} {
+		for _, op := range list {
+			if !seen[op] {
+				seen[op] = true
+				operators = append(operators, op)
+			}
+		}
+	}
+
+	return &MongoSuggester{
+		catalog:     catalog,
+		schemas:     schemas,
+		ttl:         ttl,
+		now:         time.Now,
+		operators:   operators,
+		collections: make(map[string]cachedNames),
+	}
+}
+
+func (s *MongoSuggester) SuggestFunction(name string) (string, bool) {
+	return closest(name, entities.KnownFunctions)
+}
+
+func (s *MongoSuggester) SuggestOperator(name string) (string, bool) {
+	if !strings.HasPrefix(name, "$") {
+		name = "$" + name
+	}
+	return closest(name, s.operators)
+}
+
+func (s *MongoSuggester) SuggestCollection(ctx context.Context, name string) (string, bool) {
+	collections, ok := s.listCollections(ctx)
+	if !ok {
+		return "", false
+	}
+	return closest(name, collections)
+}
+
+// SuggestField compara name con los campos de primer nivel del esquema
+// inferido de la colección en la base de datos de la sesión.
+func (s *MongoSuggester) SuggestField(ctx context.Context, collection, name string) (string, bool) {
+	database := entities.SessionFromContext(ctx).Database()
+	if s.schemas == nil || database == "" {
+		return "", false
+	}
+	schema, err := s.schemas.InferSchema(ctx, database, collection)
+	if err != nil {
+		return "", false
+	}
+
+	var fields []string
+	for _, field := range schema.Fields {
+		if !strings.Contains(field.Path, ".") {
+			fields = append(fields, field.Path)
+		}
+	}
+	return closest(name, fields)
+}
+
+// listCollections devuelve las colecciones de la base de datos de la sesión,
+// de la caché si se consultaron hace menos de ttl.
+func (s *MongoSuggester) listCollections(ctx context.Context) ([]string, bool) {
+	database := entities.SessionFromContext(ctx).Database()
+	if s.catalog == nil || database == "" {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	cached, ok := s.collections[database]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.fetchedAt) < s.ttl {
+		return cached.names, true
+	}
+
+	collections, err := s.catalog.ListCollections(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	s.collections[database] = cachedNames{names: collections, fetchedAt: s.now()}
+	s.mu.Unlock()
+	return collections, true
+}
+
+// closest devuelve el candidato más cercano a name. Si name ya es un
+// candidato exacto no hay nada que sugerir.
+func closest(name string, candidates []string) (string, bool) {
+	best := ""
+	bestDistance := maxDistance(name) + 1
+
+	for _, candidate := range candidates {
+		if candidate == name {
+			return "", false
+		}
+		d := distance(strings.ToLower(name), strings.ToLower(candidate))
+		if d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+
+	if best == "" {
+		return "", false
+	}
+	return best, true
+}
+
+// maxDistance limita cuántas ediciones se toleran según la longitud del
+// nombre, para no sugerir palabras que no tienen relación.
+func maxDistance(name string) int {
+	switch n := len(name); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// distance calcula la distancia de Damerau-Levenshtein (variante de
+// alineamiento óptimo), que cuenta una transposición como una sola edición.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return rows[len(ra)][len(rb)]
+}
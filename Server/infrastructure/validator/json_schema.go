@@ -0,0 +1,481 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// Tipos BSON que satisface cada alias de "type" (JSON Schema) y de
+// "bsonType" que no sea un tipo concreto.
+var schemaTypeAliases = map[string][]string{
+	"number":  {"int", "long", "double", "decimal"},
+	"boolean": {"bool"},
+}
+
+// validateDocumentSchema comprueba un documento completo (insertOne,
+// replaceOne) contra un $jsonSchema.
+func (v *MongoValidator) validateDocumentSchema(schema, document map[string]interface{}) error {
+	return v.validateSchemaValue(schema, "", document)
+}
+
+// validateSetSchema comprueba los valores de $set contra el subesquema de
+// cada ruta. Las rutas que el esquema no describe no se revisan, salvo que
+// additionalProperties las prohíba.
+func (v *MongoValidator) validateSetSchema(schema, update map[string]interface{}) error {
+	fields, ok := update["$set"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, path := range sortedKeys(fields) {
+		subschema, err := schemaForPath(schema, path)
+		if err != nil {
+			return err
+		}
+		if subschema == nil {
+			continue
+		}
+		if err := v.validateSchemaValue(subschema, path, fields[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaForPath recorre properties/items siguiendo una ruta con puntos.
+func schemaForPath(schema map[string]interface{}, path string) (map[string]interface{}, error) {
+	current := schema
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if current == nil {
+			return nil, nil
+		}
+
+		if isArrayIndex(segment) {
+			items, _ := current["items"].(map[string]interface{})
+			current = items
+			continue
+		}
+
+		if properties, ok := current["properties"].(map[string]interface{}); ok {
+			if property, ok := properties[segment].(map[string]interface{}); ok {
+				current = property
+				continue
+			}
+		}
+
+		switch additional := current["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return nil, entities.NewDiagnostic(i18n.SemSchemaAdditional, strings.Join(segments[:i+1], "."))
+			}
+			current = nil
+		case map[string]interface{}:
+			current = additional
+		default:
+			current = nil
+		}
+	}
+	return current, nil
+}
+
+// validateSchemaValue aplica las palabras clave de $jsonSchema a un valor.
+// Devuelve el primer incumplimiento encontrado, indicando la ruta del campo.
+func (v *MongoValidator) validateSchemaValue(schema map[string]interface{}, path string, value interface{}) error {
+	if err := checkSchemaType(schema, path, value); err != nil {
+		return err
+	}
+
+	if allowed, ok := schema["enum"].([]interface{}); ok && !containsValue(allowed, value) {
+		return entities.NewDiagnostic(i18n.SemSchemaEnum, fieldLabel(path), formatValues(allowed))
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if err := checkStringKeywords(schema, path, typed); err != nil {
+			return err
+		}
+	case int, float64:
+		if err := checkNumberKeywords(schema, path, toFloat(typed)); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := v.checkArrayKeywords(schema, path, typed); err != nil {
+			return err
+		}
+	case map[string]interface{}:
+		if err := v.checkObjectKeywords(schema, path, typed); err != nil {
+			return err
+		}
+	}
+
+	return v.checkCombinators(schema, path, value)
+}
+
+func checkSchemaType(schema map[string]interface{}, path string, value interface{}) error {
+	var expected []string
+	for _, keyword := range []string{"bsonType", "type"} {
+		switch declared := schema[keyword].(type) {
+		case string:
+			expected = append(expected, declared)
+		case []interface{}:
+			for _, name := range declared {
+				if s, ok := name.(string); ok {
+					expected = append(expected, s)
+				}
+			}
+		}
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+
+	actual := bsonTypeOf(value)
+	for _, name := range expected {
+		if name == actual || (name == "long" && actual == "int") {
+			return nil
+		}
+		for _, alias := range schemaTypeAliases[name] {
+			if alias == actual {
+				return nil
+			}
+		}
+	}
+
+	return entities.NewDiagnostic(i18n.SemSchemaType, fieldLabel(path), strings.Join(expected, " | "), actual)
+}
+
+func checkStringKeywords(schema map[string]interface{}, path, value string) error {
+	length := len([]rune(value))
+	if min, ok := schemaNumber(schema, "minLength"); ok && float64(length) < min {
+		return entities.NewDiagnostic(i18n.SemSchemaMinLength, fieldLabel(path), int(min))
+	}
+	if max, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > max {
+		return entities.NewDiagnostic(i18n.SemSchemaMaxLength, fieldLabel(path), int(max))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(value) {
+			return entities.NewDiagnostic(i18n.SemSchemaPattern, fieldLabel(path), pattern)
+		}
+	}
+	return nil
+}
+
+func checkNumberKeywords(schema map[string]interface{}, path string, value float64) error {
+	if min, ok := schemaNumber(schema, "minimum"); ok {
+		if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && value <= min {
+			return entities.NewDiagnostic(i18n.SemSchemaMinimum, fieldLabel(path), ">", formatNumber(min))
+		} else if value < min {
+			return entities.NewDiagnostic(i18n.SemSchemaMinimum, fieldLabel(path), ">=", formatNumber(min))
+		}
+	}
+	if max, ok := schemaNumber(schema, "maximum"); ok {
+		if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && value >= max {
+			return entities.NewDiagnostic(i18n.SemSchemaMaximum, fieldLabel(path), "<", formatNumber(max))
+		} else if value > max {
+			return entities.NewDiagnostic(i18n.SemSchemaMaximum, fieldLabel(path), "<=", formatNumber(max))
+		}
+	}
+	if divisor, ok := schemaNumber(schema, "multipleOf"); ok && divisor != 0 {
+		if quotient := value / divisor; quotient != math.Trunc(quotient) {
+			return entities.NewDiagnostic(i18n.SemSchemaMultipleOf, fieldLabel(path), formatNumber(divisor))
+		}
+	}
+	return nil
+}
+
+func (v *MongoValidator) checkArrayKeywords(schema map[string]interface{}, path string, array []interface{}) error {
+	if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(array)) < min {
+		return entities.NewDiagnostic(i18n.SemSchemaMinItems, fieldLabel(path), int(min))
+	}
+	if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(array)) > max {
+		return entities.NewDiagnostic(i18n.SemSchemaMaxItems, fieldLabel(path), int(max))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range array {
+			if containsValue(array[:i], array[i]) {
+				return entities.NewDiagnostic(i18n.SemSchemaUniqueItems, fieldLabel(path))
+			}
+		}
+	}
+
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		for i, element := range array {
+			if err := v.validateSchemaValue(items, joinPath(path, strconv.Itoa(i)), element); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		// Validación por posición (tupla)
+		for i, element := range array {
+			if i >= len(items) {
+				if additional, ok := schema["additionalItems"].(bool); ok && !additional {
+					return entities.NewDiagnostic(i18n.SemSchemaMaxItems, fieldLabel(path), len(items))
+				}
+				break
+			}
+			if itemSchema, ok := items[i].(map[string]interface{}); ok {
+				if err := v.validateSchemaValue(itemSchema, joinPath(path, strconv.Itoa(i)), element); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (v *MongoValidator) checkObjectKeywords(schema map[string]interface{}, path string, document map[string]interface{}) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			field, _ := name.(string)
+			if _, present := document[field]; field != "" && !present {
+				return entities.NewDiagnostic(i18n.SemSchemaRequired, joinPath(path, field))
+			}
+		}
+	}
+
+	if min, ok := schemaNumber(schema, "minProperties"); ok && float64(len(document)) < min {
+		return entities.NewDiagnostic(i18n.SemSchemaMinProperties, fieldLabel(path), int(min))
+	}
+	if max, ok := schemaNumber(schema, "maxProperties"); ok && float64(len(document)) > max {
+		return entities.NewDiagnostic(i18n.SemSchemaMaxProperties, fieldLabel(path), int(max))
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, field := range sortedKeys(document) {
+		fieldPath := joinPath(path, field)
+
+		if property, ok := properties[field].(map[string]interface{}); ok {
+			if err := v.validateSchemaValue(property, fieldPath, document[field]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if patternSchema, ok := matchPatternProperty(schema, field); ok {
+			if err := v.validateSchemaValue(patternSchema, fieldPath, document[field]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return entities.NewDiagnostic(i18n.SemSchemaAdditional, fieldPath)
+			}
+		case map[string]interface{}:
+			if err := v.validateSchemaValue(additional, fieldPath, document[field]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dependencies, ok := schema["dependencies"].(map[string]interface{}); ok {
+		for _, field := range sortedKeys(dependencies) {
+			if _, present := document[field]; !present {
+				continue
+			}
+			switch dependency := dependencies[field].(type) {
+			case []interface{}:
+				for _, name := range dependency {
+					other, _ := name.(string)
+					if _, present := document[other]; other != "" && !present {
+						return entities.NewDiagnostic(i18n.SemSchemaRequired, joinPath(path, other))
+					}
+				}
+			case map[string]interface{}:
+				if err := v.validateSchemaValue(dependency, path, document); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCombinators aplica allOf, anyOf, oneOf y not.
+func (v *MongoValidator) checkCombinators(schema map[string]interface{}, path string, value interface{}) error {
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, option := range all {
+			if subschema, ok := option.(map[string]interface{}); ok {
+				if err := v.validateSchemaValue(subschema, path, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if alternatives, ok := schema["anyOf"].([]interface{}); ok && v.countMatches(alternatives, path, value) == 0 {
+		return entities.NewDiagnostic(i18n.SemSchemaAnyOf, fieldLabel(path))
+	}
+
+	if alternatives, ok := schema["oneOf"].([]interface{}); ok && v.countMatches(alternatives, path, value) != 1 {
+		return entities.NewDiagnostic(i18n.SemSchemaOneOf, fieldLabel(path))
+	}
+
+	if not, ok := schema["not"].(map[string]interface{}); ok && v.validateSchemaValue(not, path, value) == nil {
+		return entities.NewDiagnostic(i18n.SemSchemaNot, fieldLabel(path))
+	}
+
+	return nil
+}
+
+func (v *MongoValidator) countMatches(options []interface{}, path string, value interface{}) int {
+	matches := 0
+	for _, option := range options {
+		if subschema, ok := option.(map[string]interface{}); ok && v.validateSchemaValue(subschema, path, value) == nil {
+			matches++
+		}
+	}
+	return matches
+}
+
+func matchPatternProperty(schema map[string]interface{}, field string) (map[string]interface{}, bool) {
+	patterns, ok := schema["patternProperties"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for _, pattern := range sortedKeys(patterns) {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(field) {
+			continue
+		}
+		if subschema, ok := patterns[pattern].(map[string]interface{}); ok {
+			return subschema, true
+		}
+	}
+	return nil, false
+}
+
+// bsonTypeOf devuelve el alias BSON con el que se almacenaría el valor.
+// Además de los tipos del parser reconoce los que devuelve el driver en los
+// documentos leídos del servidor.
+func bsonTypeOf(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int:
+		if typed > math.MaxInt32 || typed < math.MinInt32 {
+			return "long"
+		}
+		return "int"
+	case float64:
+		return "double"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case primitive.ObjectID:
+		return "objectId"
+	case primitive.DateTime, time.Time:
+		return "date"
+	case primitive.Decimal128:
+		return "decimal"
+	case primitive.Binary:
+		return "binData"
+	case primitive.Regex:
+		return "regex"
+	case primitive.Timestamp:
+		return "timestamp"
+	case primitive.Null:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaNumber(schema map[string]interface{}, keyword string) (float64, bool) {
+	value, ok := schema[keyword]
+	if !ok || !isNumber(value) {
+		return 0, false
+	}
+	return toFloat(value), true
+}
+
+func toFloat(value interface{}) float64 {
+	switch n := value.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// containsValue compara números por valor, de modo que 1 y 1.0 coinciden.
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if isNumber(candidate) && isNumber(value) {
+			if toFloat(candidate) == toFloat(value) {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			formatted[i] = strconv.Quote(s)
+		} else if isNumber(value) {
+			formatted[i] = formatNumber(toFloat(value))
+		} else {
+			formatted[i] = fmt.Sprint(value)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// fieldLabel nombra el valor raíz como "documento" en los mensajes.
+func fieldLabel(path string) interface{} {
+	if path == "" {
+		return entities.NewDiagnostic(i18n.SchemaRootDocument)
+	}
+	return path
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func isArrayIndex(segment string) bool {
+	if segment == "$" || strings.HasPrefix(segment, "$[") {
+		return true
+	}
+	_, err := strconv.Atoi(segment)
+	return err == nil
+}
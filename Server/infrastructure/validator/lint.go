@@ -0,0 +1,524 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// lintRule es una regla de lint: un patrón de riesgo que no impide ejecutar
+// el comando salvo que se configure con nivel error.
+type lintRule struct {
+	id       string
+	severity entities.Severity
+	check    func(command *entities.MongoCommand) []lintHit
+	// checkSchema sustituye a check en las reglas que comparan el comando
+	// con el esquema inferido de la colección.
+	checkSchema func(command *entities.MongoCommand, schema *entities.InferredSchema) []lintHit
+}
+
+// lintHit es una coincidencia de una regla. anchor localiza el token de la
+// entrada al que se refiere, para saber en qué línea está.
+type lintHit struct {
+	diagnostic *entities.Diagnostic
+	anchor     func(previous, token *entities.Token) bool
+}
+
+// Reglas de lint en el orden en que se informan.
+var lintRules = []lintRule{
+	{id: "FIND_WITHOUT_FILTER", severity: entities.SEVERITY_WARNING, check: lintFindWithoutFilter},
+	{id: "SERVER_SIDE_JAVASCRIPT", severity: entities.SEVERITY_WARNING, check: lintServerSideJavaScript},
+	{id: "REGEX_UNANCHORED", severity: entities.SEVERITY_INFO, check: lintRegexUnanchored},
+	{id: "REGEX_CASE_INSENSITIVE", severity: entities.SEVERITY_INFO, check: lintRegexCaseInsensitive},
+	{id: "NEGATION_ONLY_PREDICATE", severity: entities.SEVERITY_WARNING, check: lintNegationOnly},
+	{id: "SET_REPLACES_SUBDOCUMENT", severity: entities.SEVERITY_WARNING, check: lintSetReplacesSubdocument},
+	{id: "UNKNOWN_FIELD", severity: entities.SEVERITY_WARNING, checkSchema: lintUnknownField},
+	{id: "FIELD_TYPE_MISMATCH", severity: entities.SEVERITY_WARNING, checkSchema: lintFieldTypeMismatch},
+	{id: "NEW_TOP_LEVEL_FIELD", severity: entities.SEVERITY_INFO, checkSchema: lintNewTopLevelField},
+}
+
+var disableDirective = regexp.MustCompile(`//\s*analyzer-disable-next-line\b(.*)$`)
+
+// LintRuleIDs devuelve los IDs de las reglas disponibles.
+func LintRuleIDs() []string {
+	ids := make([]string, len(lintRules))
+	for i, rule := range lintRules {
+		ids[i] = rule.id
+	}
+	return ids
+}
+
+// ParseLintConfig valida los IDs de regla y los niveles recibidos en la
+// configuración o en una petición.
+func ParseLintConfig(values map[string]string) (entities.LintConfig, error) {
+	config := make(entities.LintConfig, len(values))
+	for id, value := range values {
+		if !isLintRule(id) {
+			return nil, entities.NewDiagnostic(i18n.LintUnknownRule, id, strings.Join(LintRuleIDs(), ", "))
+		}
+		severity, ok := entities.ParseSeverity(value)
+		if !ok {
+			return nil, entities.NewDiagnostic(i18n.LintInvalidSeverity, value, id)
+		}
+		config[id] = severity
+	}
+	return config, nil
+}
+
+// LoadLintConfig lee la configuración de reglas de un archivo JSON:
+//
+//	{ "rules": { "FIND_WITHOUT_FILTER": "error", "REGEX_UNANCHORED": "off" } }
+func LoadLintConfig(path string) (entities.LintConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Rules map[string]string `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	return ParseLintConfig(file.Rules)
+}
+
+// Lint aplica las reglas al comando con la configuración del validador y,
+// encima, la de la petición. Las directivas
+// "// analyzer-disable-next-line REGLA" de command.Source suprimen las
+// reglas indicadas (o todas, si no se indica ninguna) en la línea siguiente.
+func (v *MongoValidator) Lint(ctx context.Context, command *entities.MongoCommand, overrides entities.LintConfig) []entities.LintFinding {
+	if !command.IsValid {
+		return nil
+	}
+
+	trace := entities.TraceFromContext(ctx)
+	config := v.lint.Merge(overrides)
+	suppressed := parseDirectives(command.Source)
+	leaves := astLeaves(command.AST)
+
+	var schema *entities.InferredSchema
+	schemaLoaded := false
+
+	var findings []entities.LintFinding
+	for _, rule := range lintRules {
+		severity, ok := config[rule.id]
+		if !ok {
+			severity = rule.severity
+		}
+		if severity == entities.SEVERITY_OFF {
+			continue
+		}
+
+		var hits []lintHit
+		if rule.checkSchema != nil {
+			if !schemaLoaded {
+				schema = v.inferredSchema(ctx, command)
+				schemaLoaded = true
+			}
+			if schema == nil {
+				continue
+			}
+			hits = rule.checkSchema(command, schema)
+		} else {
+			hits = rule.check(command)
+		}
+
+		var reported error
+		for _, hit := range hits {
+			line := lineOf(leaves, hit.anchor)
+			if suppressed.covers(line, rule.id) {
+				continue
+			}
+			findings = append(findings, entities.LintFinding{
+				Rule:       rule.id,
+				Severity:   severity,
+				Line:       line,
+				Diagnostic: hit.diagnostic,
+			})
+			reported = hit.diagnostic
+		}
+		trace.RecordCheck("lint "+rule.id, reported)
+	}
+
+	return findings
+}
+
+// inferredSchema devuelve el esquema inferido de la colección del comando,
+// o nil si no hay inferencia configurada, falla o la colección está vacía.
+func (v *MongoValidator) inferredSchema(ctx context.Context, command *entities.MongoCommand) *entities.InferredSchema {
+	if v.inferred == nil || command.Database == "" || command.Collection == "" {
+		return nil
+	}
+	schema, err := v.inferred.InferSchema(ctx, command.Database, command.Collection)
+	if err != nil || schema.SampleSize == 0 {
+		return nil
+	}
+	return schema
+}
+
+func isLintRule(id string) bool {
+	for _, rule := range lintRules {
+		if rule.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressions guarda, por línea, las reglas desactivadas. Un conjunto
+// vacío desactiva todas.
+type suppressions map[int]map[string]bool
+
+func (s suppressions) covers(line int, rule string) bool {
+	rules, ok := s[line]
+	return ok && (len(rules) == 0 || rules[rule])
+}
+
+// parseDirectives busca las directivas en la entrada. Las líneas se cuentan
+// igual que en el lexer, que descarta los espacios iniciales.
+func parseDirectives(source string) suppressions {
+	result := suppressions{}
+	for i, text := range strings.Split(strings.TrimSpace(source), "\n") {
+		match := disableDirective.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		rules := map[string]bool{}
+		for _, rule := range strings.FieldsFunc(match[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			rules[rule] = true
+		}
+		result[i+2] = rules // la línea i (base 0) es la i+1; se suprime la siguiente
+	}
+	return result
+}
+
+func astLeaves(node *entities.ASTNode) []*entities.Token {
+	var leaves []*entities.Token
+	var visit func(*entities.ASTNode)
+	visit = func(n *entities.ASTNode) {
+		if n == nil {
+			return
+		}
+		if n.IsLeaf() && n.Token != nil {
+			leaves = append(leaves, n.Token)
+		}
+		for _, child := range n.Children {
+			visit(child)
+		}
+	}
+	visit(node)
+	return leaves
+}
+
+// lineOf devuelve la línea del primer token que cumple anchor, o la del
+// primer token del comando.
+func lineOf(leaves []*entities.Token, anchor func(previous, token *entities.Token) bool) int {
+	if len(leaves) == 0 {
+		return 1
+	}
+	if anchor != nil {
+		for i, token := range leaves {
+			var previous *entities.Token
+			if i > 0 {
+				previous = leaves[i-1]
+			}
+			if anchor(previous, token) {
+				return token.Line
+			}
+		}
+	}
+	return leaves[0].Line
+}
+
+// atFunction ancla el hallazgo en el nombre de la función del comando.
+func atFunction(name string) func(previous, token *entities.Token) bool {
+	return func(_, token *entities.Token) bool {
+		return token.Type == entities.FUNCTION && token.Value == name
+	}
+}
+
+// atKey ancla el hallazgo en una clave escrita como $op, identificador o
+// cadena.
+func atKey(key string) func(previous, token *entities.Token) bool {
+	return func(previous, token *entities.Token) bool {
+		if token.Type == entities.STRING && token.Value == key {
+			return true
+		}
+		if strings.HasPrefix(key, "$") {
+			return token.Type == entities.IDENTIFIER && token.Value == key[1:] &&
+				previous != nil && previous.Type == entities.DOLLAR_SIGN
+		}
+		return token.Type == entities.IDENTIFIER && token.Value == key
+	}
+}
+
+func lintFindWithoutFilter(command *entities.MongoCommand) []lintHit {
+	if command.Type != entities.FIND || len(command.Filter) > 0 {
+		return nil
+	}
+	return []lintHit{{entities.NewDiagnostic(i18n.LintFindWithoutFilter, command.Collection), atFunction("find")}}
+}
+
+func lintServerSideJavaScript(command *entities.MongoCommand) []lintHit {
+	var hits []lintHit
+	forEachDocument(command, func(_ string, document map[string]interface{}) {
+		for _, operator := range []string{"$where", "$function", "$accumulator"} {
+			if _, ok := document[operator]; ok {
+				hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintServerSideJavaScript, operator), atKey(operator)})
+			}
+		}
+	})
+	return hits
+}
+
+func lintRegexUnanchored(command *entities.MongoCommand) []lintHit {
+	var hits []lintHit
+	forEachDocument(command, func(path string, document map[string]interface{}) {
+		pattern, ok := document["$regex"].(string)
+		if ok && !strings.HasPrefix(pattern, "^") && !strings.HasPrefix(pattern, `\A`) {
+			hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintRegexUnanchored, path, pattern), atKey("$regex")})
+		}
+	})
+	return hits
+}
+
+func lintRegexCaseInsensitive(command *entities.MongoCommand) []lintHit {
+	var hits []lintHit
+	forEachDocument(command, func(path string, document map[string]interface{}) {
+		options, ok := document["$options"].(string)
+		if ok && strings.Contains(options, "i") {
+			hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintRegexCaseInsensitive, path), atKey("$options")})
+		}
+	})
+	return hits
+}
+
+// lintNegationOnly detecta filtros cuyas únicas condiciones son $ne o $nin,
+// que no pueden usar índices de forma selectiva.
+func lintNegationOnly(command *entities.MongoCommand) []lintHit {
+	if len(command.Filter) == 0 {
+		return nil
+	}
+
+	var first string
+	for _, field := range sortedKeys(command.Filter) {
+		condition, ok := command.Filter[field].(map[string]interface{})
+		if !ok || len(condition) == 0 {
+			return nil
+		}
+		for operator := range condition {
+			if operator != "$ne" && operator != "$nin" {
+				return nil
+			}
+			if first == "" {
+				first = operator
+			}
+		}
+	}
+	return []lintHit{{entities.NewDiagnostic(i18n.LintNegationOnly), atKey(first)}}
+}
+
+// lintSetReplacesSubdocument avisa cuando $set asigna un documento completo
+// a un campo, lo que reemplaza el subdocumento entero en lugar de modificar
+// solo algunos de sus campos.
+func lintSetReplacesSubdocument(command *entities.MongoCommand) []lintHit {
+	if command.Type != entities.UPDATE_ONE {
+		return nil
+	}
+	fields, ok := command.Update["$set"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var hits []lintHit
+	for _, field := range sortedKeys(fields) {
+		value, ok := fields[field].(map[string]interface{})
+		if ok && len(value) > 0 && !hasOperatorKeys(value) {
+			hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintSetReplacesSubdocument, field), atKey(field)})
+		}
+	}
+	return hits
+}
+
+// forEachDocument visita todos los documentos del filtro, la actualización y
+// el documento del comando, con la ruta del campo que los contiene.
+func forEachDocument(command *entities.MongoCommand, visit func(path string, document map[string]interface{})) {
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			visit(path, typed)
+			for _, key := range sortedKeys(typed) {
+				child := path
+				if !strings.HasPrefix(key, "$") {
+					child = joinPath(path, key)
+				}
+				walk(child, typed[key])
+			}
+		case []interface{}:
+			for _, element := range typed {
+				walk(path, element)
+			}
+		}
+	}
+
+	walk("", command.Filter)
+	walk("", command.Update)
+	walk("", command.UpdatePipeline)
+	walk("", command.Document)
+}
+
+// lintUnknownField avisa de los campos del filtro que no aparecen en la
+// muestra; suelen ser errores de escritura.
+func lintUnknownField(command *entities.MongoCommand, schema *entities.InferredSchema) []lintHit {
+	var hits []lintHit
+	forEachPredicate(command.Filter, func(path string, _ interface{}) {
+		if !knownPath(schema, path) {
+			namespace := schema.Database + "." + schema.Collection
+			hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintUnknownField, path, schema.SampleSize, namespace), atKey(path)})
+		}
+	})
+	return hits
+}
+
+// lintFieldTypeMismatch avisa cuando el filtro compara un campo con un valor
+// de un tipo que no aparece en la muestra, por ejemplo una cadena con un
+// número.
+func lintFieldTypeMismatch(command *entities.MongoCommand, schema *entities.InferredSchema) []lintHit {
+	var hits []lintHit
+	forEachPredicate(command.Filter, func(path string, condition interface{}) {
+		field, ok := schema.Field(path)
+		if !ok {
+			return
+		}
+		observed := observedFamilies(field)
+		if len(observed) == 0 {
+			return
+		}
+		for _, value := range comparedValues(condition) {
+			family := typeFamily(bsonTypeOf(value))
+			if !observed[family] {
+				hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintFieldTypeMismatch, path, family, joinFamilies(observed)), atKey(path)})
+				return
+			}
+		}
+	})
+	return hits
+}
+
+// lintNewTopLevelField avisa cuando una actualización crea un campo de
+// primer nivel que ningún documento de la muestra tiene.
+func lintNewTopLevelField(command *entities.MongoCommand, schema *entities.InferredSchema) []lintHit {
+	if command.Type != entities.UPDATE_ONE {
+		return nil
+	}
+
+	var hits []lintHit
+	reported := map[string]bool{}
+	for _, operator := range sortedKeys(command.Update) {
+		fields, ok := command.Update[operator].(map[string]interface{})
+		if !ok || operator == "$unset" || operator == "$pull" || operator == "$pullAll" || operator == "$pop" {
+			continue
+		}
+		for _, path := range sortedKeys(fields) {
+			target := path
+			if operator == "$rename" {
+				if target, ok = fields[path].(string); !ok {
+					continue
+				}
+			}
+			top := strings.Split(target, ".")[0]
+			if _, known := schema.Field(top); known || reported[top] {
+				continue
+			}
+			reported[top] = true
+			hits = append(hits, lintHit{entities.NewDiagnostic(i18n.LintNewTopLevelField, top, schema.Database+"."+schema.Collection), atKey(target)})
+		}
+	}
+	return hits
+}
+
+// forEachPredicate visita las condiciones de campo del filtro, también las
+// que están dentro de $and, $or y $nor.
+func forEachPredicate(filter map[string]interface{}, visit func(path string, condition interface{})) {
+	for _, key := range sortedKeys(filter) {
+		switch key {
+		case "$and", "$or", "$nor":
+			clauses, _ := filter[key].([]interface{})
+			for _, clause := range clauses {
+				if document, ok := clause.(map[string]interface{}); ok {
+					forEachPredicate(document, visit)
+				}
+			}
+		default:
+			if !strings.HasPrefix(key, "$") {
+				visit(key, filter[key])
+			}
+		}
+	}
+}
+
+// comparedValues devuelve los valores escalares con los que una condición
+// compara el campo: la igualdad implícita, $eq, $ne, los rangos, $in y $nin.
+func comparedValues(condition interface{}) []interface{} {
+	document, ok := condition.(map[string]interface{})
+	if !ok {
+		return scalars(condition)
+	}
+	if !hasOperatorKeys(document) {
+		return nil
+	}
+
+	var values []interface{}
+	for _, operator := range sortedKeys(document) {
+		switch operator {
+		case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+			values = append(values, scalars(document[operator])...)
+		case "$in", "$nin":
+			elements, _ := document[operator].([]interface{})
+			for _, element := range elements {
+				values = append(values, scalars(element)...)
+			}
+		}
+	}
+	return values
+}
+
+func scalars(value interface{}) []interface{} {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// observedFamilies devuelve las familias de tipos del campo y de sus
+// elementos, sin contar null.
+func observedFamilies(field *entities.FieldSchema) map[string]bool {
+	families := map[string]bool{}
+	for _, types := range []map[string]int{field.Types, field.ElementTypes} {
+		for bsonType := range types {
+			if bsonType != "null" {
+				families[typeFamily(bsonType)] = true
+			}
+		}
+	}
+	return families
+}
+
+func joinFamilies(families map[string]bool) string {
+	names := make([]string, 0, len(families))
+	for family := range families {
+		names = append(names, family)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
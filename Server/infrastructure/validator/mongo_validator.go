@@ -1,51 +1,164 @@
 package validator
 
 import (
-	"fmt"
-	"regexp"
+	"context"
+	"strings"
+
 	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/domain/interfaces"
 )
 
-type MongoValidator struct{}
+type MongoValidator struct {
+	schemas  interfaces.SchemaSource
+	catalog  interfaces.NamespaceCatalog
+	inferred interfaces.SchemaInferrer
+	lint     entities.LintConfig
+}
 
-func NewMongoValidator() *MongoValidator {
-	return &MongoValidator{}
+// NewMongoValidator crea el validador. Las dependencias son opcionales:
+// con schemas, los documentos insertados o reemplazados y los valores de
+// $set se comprueban contra el $jsonSchema de la colección; con catalog se
+// detectan bases de datos que solo difieren en mayúsculas; con inferred las
+// reglas de lint comparan los filtros y las actualizaciones con el esquema
+// inferido de una muestra de documentos; lint cambia el nivel por defecto de
+// las reglas de lint.
+func NewMongoValidator(schemas interfaces.SchemaSource, catalog interfaces.NamespaceCatalog, inferred interfaces.SchemaInferrer, lint entities.LintConfig) *MongoValidator {
+	return &MongoValidator{schemas: schemas, catalog: catalog, inferred: inferred, lint: lint}
 }
 
-func (v *MongoValidator) ValidateSemantics(command *entities.MongoCommand) error {
+func (v *MongoValidator) ValidateSemantics(ctx context.Context, command *entities.MongoCommand) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if !command.IsValid {
-		return fmt.Errorf("comando sintácticamente inválido")
+		return entities.NewDiagnostic(i18n.SemInvalidSyntax)
+	}
+
+	trace := entities.TraceFromContext(ctx)
+
+	// El nombre de la base de datos se comprueba en cualquier comando que la
+	// indique, no solo en use: también es la que recibe la política y el
+	// ejecutor
+	if command.Type == entities.USE_DATABASE || command.Database != "" {
+		if err := v.check(trace, "validateDatabaseName", func() error { return v.validateDatabaseName(command.Database) }); err != nil {
+			return err
+		}
+	}
+
+	if command.Collection != "" {
+		// Las lecturas pueden consultar colecciones reservadas (system.*)
+		if command.Type != entities.FIND {
+			if err := v.check(trace, "validateCollectionName", func() error { return v.validateCollectionName(command.Collection) }); err != nil {
+				return err
+			}
+		}
+		if err := v.check(trace, "validateNamespace", func() error { return v.validateNamespace(command.Database, command.Collection) }); err != nil {
+			return err
+		}
 	}
 
 	switch command.Type {
 	case entities.USE_DATABASE:
-		return v.validateDatabaseName(command.Database)
-	case entities.CREATE_COLLECTION:
-		return v.validateCollectionName(command.Collection)
+		if v.catalog == nil {
+			return nil
+		}
+		return v.check(trace, "validateDatabaseClash", func() error { return v.validateDatabaseClash(ctx, command.Database) })
 	case entities.INSERT_ONE:
-		return v.validateInsertDocument(command.Document)
+		if err := v.check(trace, "validateInsertDocument", func() error { return v.validateInsertDocument(command.Document) }); err != nil {
+			return err
+		}
+		if err := v.check(trace, "validateDocumentStructure", func() error { return v.validateDocumentStructure(command.Document) }); err != nil {
+			return err
+		}
+		return v.validateJSONSchema(ctx, trace, command)
+	case entities.FIND:
+		return v.validateFilterChecks(trace, command.Filter)
 	case entities.UPDATE_ONE:
-		return v.validateUpdateCommand(command.Filter, command.Update)
+		if err := v.check(trace, "validateUpdateCommand", func() error { return v.validateUpdateCommand(command.Filter, command.Update, command.UpdatePipeline) }); err != nil {
+			return err
+		}
+		if err := v.validateFilterChecks(trace, command.Filter); err != nil {
+			return err
+		}
+		return v.validateJSONSchema(ctx, trace, command)
+	case entities.REPLACE_ONE:
+		if err := v.check(trace, "validateReplaceCommand", func() error { return v.validateReplaceCommand(command.Filter, command.Document) }); err != nil {
+			return err
+		}
+		if err := v.check(trace, "validateDocumentStructure", func() error { return v.validateDocumentStructure(command.Document) }); err != nil {
+			return err
+		}
+		if err := v.validateFilterChecks(trace, command.Filter); err != nil {
+			return err
+		}
+		return v.validateJSONSchema(ctx, trace, command)
 	case entities.DELETE_ONE:
-		return v.validateDeleteCommand(command.Filter)
+		// Un filtro vacío es un error semántico, no una regla de lint que
+		// se pueda desactivar
+		if err := v.check(trace, "validateDeleteCommand", func() error { return v.validateDeleteCommand(command.Filter) }); err != nil {
+			return err
+		}
+		return v.validateFilterChecks(trace, command.Filter)
 	}
 
 	return nil
 }
 
+// validateFilterChecks aplica al filtro la comprobación de anidamiento y la
+// de operadores de consulta.
+func (v *MongoValidator) validateFilterChecks(trace *entities.AnalysisTrace, filter map[string]interface{}) error {
+	if err := v.check(trace, "validateNesting", func() error { return validateNesting(filter) }); err != nil {
+		return err
+	}
+	return v.check(trace, "validateFilter", func() error { return v.validateFilter(filter) })
+}
+
+// validateJSONSchema comprueba el comando contra el $jsonSchema de la
+// colección. Si no hay esquema, o no se puede obtener (por ejemplo sin
+// conexión), la comprobación se omite.
+func (v *MongoValidator) validateJSONSchema(ctx context.Context, trace *entities.AnalysisTrace, command *entities.MongoCommand) error {
+	if v.schemas == nil {
+		return nil
+	}
+
+	schema, err := v.schemas.CollectionSchema(ctx, command.Database, command.Collection)
+	if err != nil || schema == nil {
+		return ctx.Err()
+	}
+
+	return v.check(trace, "validateJSONSchema", func() error {
+		if command.Type == entities.UPDATE_ONE {
+			return v.validateSetSchema(schema, command.Update)
+		}
+		return v.validateDocumentSchema(schema, command.Document)
+	})
+}
+
+// check ejecuta una comprobación y la registra en el trazado si lo hay.
+func (v *MongoValidator) check(trace *entities.AnalysisTrace, name string, validate func() error) error {
+	err := validate()
+	trace.RecordCheck(name, err)
+	return err
+}
+
 func (v *MongoValidator) validateDatabaseName(name string) error {
 	if name == "" {
-		return fmt.Errorf("el nombre de la base de datos no puede estar vacío")
+		return entities.NewDiagnostic(i18n.SemDatabaseNameEmpty)
+	}
+
+	if strings.ContainsRune(name, 0) {
+		return entities.NewDiagnostic(i18n.SemDatabaseNameNull)
 	}
 
-	// MongoDB database name restrictions
-	invalidChars := regexp.MustCompile(`[/\\. "$<>:|?*]`)
-	if invalidChars.MatchString(name) {
-		return fmt.Errorf("el nombre de la base de datos contiene caracteres inválidos")
+	// MongoDB database name restrictions (Unix y Windows)
+	if strings.ContainsAny(name, invalidDatabaseChars) {
+		return entities.NewDiagnostic(i18n.SemDatabaseNameChars)
 	}
 
-	if len(name) > 64 {
-		return fmt.Errorf("el nombre de la base de datos es demasiado largo (máximo 64 caracteres)")
+	if len(name) > maxDatabaseNameBytes {
+		return entities.NewDiagnostic(i18n.SemDatabaseNameTooLong)
 	}
 
 	return nil
@@ -53,11 +166,19 @@ func (v *MongoValidator) validateDatabaseName(name string) error {
 
 func (v *MongoValidator) validateCollectionName(name string) error {
 	if name == "" {
-		return fmt.Errorf("el nombre de la colección no puede estar vacío")
+		return entities.NewDiagnostic(i18n.SemCollectionNameEmpty)
+	}
+
+	if strings.ContainsRune(name, 0) {
+		return entities.NewDiagnostic(i18n.SemCollectionNameNull)
 	}
 
-	if name[0] == '$' {
-		return fmt.Errorf("el nombre de la colección no puede comenzar con '$'")
+	if strings.ContainsRune(name, '$') {
+		return entities.NewDiagnostic(i18n.SemCollectionNameDollar)
+	}
+
+	if strings.HasPrefix(name, reservedCollectionTag) {
+		return entities.NewDiagnostic(i18n.SemCollectionNameReserved, name)
 	}
 
 	return nil
@@ -65,46 +186,48 @@ func (v *MongoValidator) validateCollectionName(name string) error {
 
 func (v *MongoValidator) validateInsertDocument(doc map[string]interface{}) error {
 	if len(doc) == 0 {
-		return fmt.Errorf("el documento a insertar no puede estar vacío")
+		return entities.NewDiagnostic(i18n.SemInsertEmpty)
 	}
 
 	// Validar que las claves no contengan caracteres especiales
 	for key := range doc {
 		if key == "" {
-			return fmt.Errorf("las claves del documento no pueden estar vacías")
+			return entities.NewDiagnostic(i18n.SemDocumentKeyEmpty)
 		}
 		if key[0] == '$' {
-			return fmt.Errorf("las claves del documento no pueden comenzar con '$'")
+			return entities.NewDiagnostic(i18n.SemDocumentKeyDollar)
 		}
 	}
 
 	return nil
 }
 
-func (v *MongoValidator) validateUpdateCommand(filter, update map[string]interface{}) error {
+func (v *MongoValidator) validateUpdateCommand(filter, update map[string]interface{}, pipeline []interface{}) error {
 	if len(filter) == 0 {
-		return fmt.Errorf("el filtro de actualización no puede estar vacío")
+		return entities.NewDiagnostic(i18n.SemUpdateFilterEmpty)
+	}
+
+	if pipeline != nil {
+		return v.validateUpdatePipeline(pipeline)
 	}
 
 	if len(update) == 0 {
-		return fmt.Errorf("la actualización no puede estar vacía")
-	}
-
-	// Validar que update tenga operadores válidos
-	hasValidOperator := false
-	validOperators := []string{"$set", "$unset", "$inc", "$push", "$pull"}
-	
-	for key := range update {
-		for _, op := range validOperators {
-			if key == op {
-				hasValidOperator = true
-				break
-			}
-		}
+		return entities.NewDiagnostic(i18n.SemUpdateEmpty)
 	}
 
-	if !hasValidOperator {
-		return fmt.Errorf("la actualización debe contener al menos un operador válido ($set, $unset, $inc, etc.)")
+	return v.validateUpdateDocument(update)
+}
+
+func (v *MongoValidator) validateReplaceCommand(filter, replacement map[string]interface{}) error {
+	if len(filter) == 0 {
+		return entities.NewDiagnostic(i18n.SemReplaceFilterEmpty)
+	}
+
+	// El reemplazo es un documento completo: no admite operadores
+	for key := range replacement {
+		if key != "" && key[0] == '$' {
+			return entities.NewDiagnostic(i18n.SemReplaceOperator, key)
+		}
 	}
 
 	return nil
@@ -112,7 +235,7 @@ func (v *MongoValidator) validateUpdateCommand(filter, update map[string]interfa
 
 func (v *MongoValidator) validateDeleteCommand(filter map[string]interface{}) error {
 	if len(filter) == 0 {
-		return fmt.Errorf("el filtro de eliminación no puede estar vacío")
+		return entities.NewDiagnostic(i18n.SemDeleteFilterEmpty)
 	}
 
 	return nil
@@ -0,0 +1,186 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+	"mongo-analyzer/infrastructure/lexer"
+	"mongo-analyzer/infrastructure/parser"
+	"mongo-analyzer/infrastructure/validator"
+)
+
+// semanticCase es un comando y el código del error semántico que debe
+// producir, o "" si es válido.
+type semanticCase struct {
+	input string
+	code  string
+}
+
+// checkSemantics analiza cada caso con el lexer y el parser reales y
+// compara el error de ValidateSemantics con el esperado.
+func checkSemantics(t *testing.T, cases []semanticCase) {
+	t.Helper()
+	checkSemanticsWith(t, validator.NewMongoValidator(nil, nil, nil, nil), cases)
+}
+
+func checkSemanticsWith(t *testing.T, v *validator.MongoValidator, cases []semanticCase) {
+	t.Helper()
+
+	l := lexer.NewMongoLexer()
+	p := parser.NewMongoParser()
+	ctx := entities.ContextWithSession(context.Background(), entities.NewSession("test", time.Now()))
+
+	for _, tc := range cases {
+		tokens, err := l.Tokenize(tc.input)
+		if err != nil {
+			t.Fatalf("%s: error léxico: %v", tc.input, err)
+		}
+		command, err := p.Parse(ctx, tokens)
+		if err != nil {
+			t.Fatalf("%s: error sintáctico: %v", tc.input, err)
+		}
+		if command.Database == "" {
+			command.Database = "escuela"
+		}
+
+		var code string
+		if err := v.ValidateSemantics(ctx, command); err != nil {
+			diagnostic, ok := err.(*entities.Diagnostic)
+			if !ok {
+				t.Fatalf("%s: error sin diagnóstico: %v", tc.input, err)
+			}
+			code = diagnostic.Code
+		}
+		if code != tc.code {
+			t.Errorf("%s: error %q, se esperaba %q", tc.input, code, tc.code)
+		}
+	}
+}
+
+func TestValidateSemanticsQueryOperators(t *testing.T) {
+	checkSemantics(t, []semanticCase{
+		{`db.alumnos.find({ "edad": { "$in": [18, 19] } })`, ""},
+		{`db.alumnos.find({ "edad": { "$in": 18 } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$nin": "18" } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "cursos": { "$size": 2 } })`, ""},
+		{`db.alumnos.find({ "cursos": { "$size": -1 } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "cursos": { "$size": 1.5 } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$gt": 18, "minimo": 1 } })`, i18n.SemMixedOperatorsAndFields},
+		{`db.alumnos.find({ "edad": { "$gte": 18, "$lt": 30 } })`, ""},
+		{`db.alumnos.find({ "edad": { "$foo": 1 } })`, i18n.SemUnknownQueryOperator},
+		{`db.alumnos.find({ "$gt": 1 })`, i18n.SemOperatorNeedsField},
+		{`db.alumnos.find({ "edad": { "$or": [] } })`, i18n.SemLogicalOperatorInField},
+		{`db.alumnos.find({ "$or": [] })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "nombre": { "$options": "i" } })`, i18n.SemOptionsWithoutRegex},
+		{`db.alumnos.find({ "nombre": { "$regex": "^A", "$options": "iq" } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$mod": [0, 1] } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$type": "texto" } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$type": 16 } })`, ""},
+		{`db.alumnos.find({ "edad": { "$type": [2, -1, 127] } })`, ""},
+		{`db.alumnos.find({ "edad": { "$type": 0 } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$type": 20 } })`, i18n.SemOperandType},
+		{`db.alumnos.find({ "edad": { "$type": [2, 15] } })`, i18n.SemOperandType},
+		{`db.alumnos.deleteOne({ "edad": { "$exists": "si" } })`, i18n.SemOperandType},
+	})
+}
+
+func TestValidateSemanticsUpdateOperators(t *testing.T) {
+	checkSemantics(t, []semanticCase{
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "a": 1 }, "$inc": { "b": 1 } })`, ""},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "a": 1 }, "$inc": { "a.b": 1 } })`, i18n.SemUpdatePathConflict},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "a": 1 }, "$unset": { "a": "" } })`, i18n.SemUpdatePathConflict},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$rename": { "a": "b" }, "$set": { "b": 1 } })`, i18n.SemUpdatePathConflict},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "a": 1 }, "nombre": "Ana" })`, i18n.SemUpdateMixedFields},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "nombre": "Ana" })`, i18n.SemUpdateNoOperator},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$foo": { "a": 1 } })`, i18n.SemUnknownUpdateOperator},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": {} })`, i18n.SemUpdateOperatorEmpty},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$inc": { "edad": "uno" } })`, i18n.SemOperandType},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$pop": { "cursos": 2 } })`, i18n.SemOperandType},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$push": { "cursos": { "$each": ["bd"], "$slice": 3 } } })`, ""},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$push": { "cursos": { "$slice": 3 } } })`, i18n.SemUpdateModifierWithoutEach},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$addToSet": { "cursos": { "$each": ["bd"], "$sort": 1 } } })`, i18n.SemUnknownUpdateModifier},
+		{`db.alumnos.updateOne({ "_id": 1 }, [{ "$set": { "a": 1 } }])`, ""},
+		{`db.alumnos.updateOne({ "_id": 1 }, [])`, i18n.SemUpdatePipelineEmpty},
+		{`db.alumnos.updateOne({ "_id": 1 }, [{ "$match": { "a": 1 } }])`, i18n.SemUpdatePipelineStage},
+	})
+}
+
+func TestValidateSemanticsNamespaces(t *testing.T) {
+	// "escuela." ocupa 8 bytes: la colección puede tener hasta 247
+	longest := strings.Repeat("c", 247)
+
+	checkSemantics(t, []semanticCase{
+		{`db.createCollection("alumnos")`, ""},
+		{`db.createCollection("system.users")`, i18n.SemCollectionNameReserved},
+		{`db.createCollection("precio$")`, i18n.SemCollectionNameDollar},
+		{`db.createCollection("` + longest + `")`, ""},
+		{`db.createCollection("` + longest + `c")`, i18n.SemNamespaceTooLong},
+		{`use escuela`, ""},
+		{`use ` + strings.Repeat("e", 64), i18n.SemDatabaseNameTooLong},
+		{`use ` + strings.Repeat("e", 64) + ` db.dropDatabase()`, i18n.SemDatabaseNameTooLong},
+		{`db.alumnos.insertOne({ "direccion": { "": 1 } })`, i18n.SemFieldNameEmptySegment},
+	})
+}
+
+// Un deleteOne({}) se rechaza en la validación semántica, sin depender de
+// la configuración de lint.
+func TestValidateSemanticsRejectsEmptyDeleteFilter(t *testing.T) {
+	checkSemantics(t, []semanticCase{
+		{`db.alumnos.deleteOne({})`, i18n.SemDeleteFilterEmpty},
+		{`db.alumnos.deleteOne({ "_id": 1 })`, ""},
+	})
+}
+
+// alumnosSchema es el $jsonSchema que se registra para escuela.alumnos.
+const alumnosSchema = `{
+	"bsonType": "object",
+	"required": ["nombre"],
+	"properties": {
+		"nombre": { "bsonType": "string", "minLength": 2 },
+		"edad": { "bsonType": "int", "minimum": 0, "maximum": 120 },
+		"curso": { "enum": ["primero", "segundo"] },
+		"direccion": {
+			"bsonType": "object",
+			"required": ["ciudad"],
+			"properties": {
+				"ciudad": { "bsonType": "string" },
+				"cp": { "bsonType": "string", "pattern": "^[0-9]{5}$" }
+			}
+		}
+	}
+}`
+
+func TestValidateSemanticsJSONSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(alumnosSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	// La sesión no tiene base de datos: el esquema se busca con la del
+	// comando
+	registry := validator.NewSchemaRegistry(nil)
+	registry.Register("escuela", "alumnos", schema)
+	v := validator.NewMongoValidator(registry, nil, nil, nil)
+
+	checkSemanticsWith(t, v, []semanticCase{
+		{`db.alumnos.insertOne({ "nombre": "Ana", "edad": 20, "curso": "primero" })`, ""},
+		{`db.alumnos.insertOne({ "nombre": 7 })`, i18n.SemSchemaType},
+		{`db.alumnos.insertOne({ "edad": 20 })`, i18n.SemSchemaRequired},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "curso": "tercero" })`, i18n.SemSchemaEnum},
+		{`db.alumnos.insertOne({ "nombre": "A" })`, i18n.SemSchemaMinLength},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "edad": -1 })`, i18n.SemSchemaMinimum},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "edad": 121 })`, i18n.SemSchemaMaximum},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "direccion": { "cp": "28001" } })`, i18n.SemSchemaRequired},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "direccion": { "ciudad": 1 } })`, i18n.SemSchemaType},
+		{`db.alumnos.insertOne({ "nombre": "Ana", "direccion": { "ciudad": "Madrid", "cp": "28" } })`, i18n.SemSchemaPattern},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "direccion.ciudad": "Madrid" } })`, ""},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "direccion.ciudad": 1 } })`, i18n.SemSchemaType},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "direccion.cp": "x" } })`, i18n.SemSchemaPattern},
+		{`db.alumnos.updateOne({ "_id": 1 }, { "$set": { "edad": 200 } })`, i18n.SemSchemaMaximum},
+		{`db.profesores.insertOne({ "nombre": 7 })`, ""},
+	})
+}
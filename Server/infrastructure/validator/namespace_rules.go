@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// Límites del servidor MongoDB.
+const (
+	maxDatabaseNameBytes  = 63
+	maxNamespaceBytes     = 255
+	maxNestingDepth       = 100
+	maxBSONDocumentBytes  = 16 * 1024 * 1024
+	reservedCollectionTag = "system."
+)
+
+// Caracteres prohibidos en nombres de base de datos: los de Unix más los
+// reservados por Windows.
+const invalidDatabaseChars = "/\\. \"$*<>:|?"
+
+// validateDatabaseClash rechaza una base de datos que solo difiere en
+// mayúsculas de otra existente: MongoDB no permite ambas.
+func (v *MongoValidator) validateDatabaseClash(ctx context.Context, name string) error {
+	existing, err := v.catalog.ListDatabases(ctx)
+	if err != nil {
+		return nil
+	}
+
+	for _, other := range existing {
+		if other != name && strings.EqualFold(other, name) {
+			return entities.NewDiagnostic(i18n.SemDatabaseNameCaseClash, name, other)
+		}
+	}
+	return nil
+}
+
+// validateNamespace comprueba el límite de bytes de "base.coleccion".
+func (v *MongoValidator) validateNamespace(database, collection string) error {
+	if database == "" {
+		return nil
+	}
+
+	namespace := database + "." + collection
+	if len(namespace) > maxNamespaceBytes {
+		return entities.NewDiagnostic(i18n.SemNamespaceTooLong, namespace, len(namespace), maxNamespaceBytes)
+	}
+	return nil
+}
+
+// validateDocumentStructure recorre un documento completo comprobando los
+// nombres de campo, la profundidad de anidamiento y el tamaño BSON estimado.
+func (v *MongoValidator) validateDocumentStructure(document map[string]interface{}) error {
+	if err := validateFieldNames(document); err != nil {
+		return err
+	}
+	if err := validateNesting(document); err != nil {
+		return err
+	}
+
+	if size := estimateBSONSize(document); size > maxBSONDocumentBytes {
+		return entities.NewDiagnostic(i18n.SemDocumentTooLarge, size, maxBSONDocumentBytes)
+	}
+	return nil
+}
+
+// validateFieldNames rechaza nombres con el carácter nulo o con segmentos
+// vacíos ("a..b", ".a", "a."), también en subdocumentos y arrays.
+func validateFieldNames(value interface{}) error {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for _, field := range sortedKeys(typed) {
+			if err := validateFieldPath(field); err != nil {
+				return err
+			}
+			if err := validateFieldNames(typed[field]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, element := range typed {
+			if err := validateFieldNames(element); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateFieldPath comprueba un nombre o ruta de campo con puntos.
+func validateFieldPath(path string) error {
+	if strings.ContainsRune(path, 0) {
+		return entities.NewDiagnostic(i18n.SemFieldNameNull, strings.ReplaceAll(path, "\x00", `\0`))
+	}
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			return entities.NewDiagnostic(i18n.SemFieldNameEmptySegment, path)
+		}
+	}
+	return nil
+}
+
+// validateNesting rechaza valores con más de maxNestingDepth niveles de
+// documentos o arrays anidados.
+func validateNesting(value interface{}) error {
+	if nestingDepth(value) > maxNestingDepth {
+		return entities.NewDiagnostic(i18n.SemDocumentTooDeep, maxNestingDepth)
+	}
+	return nil
+}
+
+func nestingDepth(value interface{}) int {
+	deepest := 0
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for _, element := range typed {
+			deepest = max(deepest, nestingDepth(element))
+		}
+	case []interface{}:
+		for _, element := range typed {
+			deepest = max(deepest, nestingDepth(element))
+		}
+	default:
+		return 0
+	}
+	return deepest + 1
+}
+
+// estimateBSONSize calcula el tamaño en bytes que tendría el valor
+// codificado en BSON, con los tipos que produce el parser.
+func estimateBSONSize(value interface{}) int {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		size := 4 + 1 // longitud + terminador
+		for key, element := range typed {
+			size += 1 + len(key) + 1 + estimateBSONSize(element) // tipo + nombre + valor
+		}
+		return size
+	case []interface{}:
+		size := 4 + 1
+		for i, element := range typed {
+			size += 1 + digits(i) + 1 + estimateBSONSize(element)
+		}
+		return size
+	case string:
+		return 4 + len(typed) + 1
+	case int:
+		if typed > math.MaxInt32 || typed < math.MinInt32 {
+			return 8
+		}
+		return 4
+	case float64:
+		return 8
+	case bool:
+		return 1
+	}
+	return 0
+}
+
+func digits(n int) int {
+	count := 1
+	for n >= 10 {
+		n /= 10
+		count++
+	}
+	return count
+}
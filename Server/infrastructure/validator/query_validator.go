@@ -0,0 +1,296 @@
+package validator
+
+import (
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// Operadores que solo pueden aparecer en la raíz de un filtro (o dentro de
+// $and/$or/$nor), nunca como condición de un campo.
+var topLevelOperators = map[string]bool{
+	"$and": true, "$or": true, "$nor": true, "$expr": true,
+	"$where": true, "$text": true, "$jsonSchema": true, "$comment": true,
+}
+
+// Operadores que se aplican a un campo: { campo: { $op: operando } }.
+var fieldOperators = map[string]bool{
+	"$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$in": true, "$nin": true, "$all": true, "$exists": true, "$type": true,
+	"$regex": true, "$options": true, "$elemMatch": true, "$size": true,
+	"$mod": true, "$not": true,
+}
+
+// Alias de tipos BSON aceptados por $type.
+var bsonTypeAliases = map[string]bool{
+	"double": true, "string": true, "object": true, "array": true, "binData": true,
+	"undefined": true, "objectId": true, "bool": true, "date": true, "null": true,
+	"regex": true, "dbPointer": true, "javascript": true, "symbol": true, "int": true,
+	"timestamp": true, "long": true, "decimal": true, "minKey": true, "maxKey": true,
+	"number": true,
+}
+
+// Números de tipo BSON aceptados por $type: los mismos tipos que los alias,
+// de -1 (minKey) a 127 (maxKey). El 0 no es un tipo y el 15
+// (javascriptWithScope) está obsoleto.
+var bsonTypeCodes = map[int]bool{
+	-1: true, 1: true, 2: true, 3: true, 4: true, 5: true, 6: true, 7: true,
+	8: true, 9: true, 10: true, 11: true, 12: true, 13: true, 14: true,
+	16: true, 17: true, 18: true, 19: true, 127: true,
+}
+
+// validateFilter comprueba los operadores de un filtro de consulta: que sean
+// conocidos, que estén en una posición válida y que sus operandos tengan el
+// tipo correcto.
+func (v *MongoValidator) validateFilter(filter map[string]interface{}) error {
+	for _, key := range sortedKeys(filter) {
+		value := filter[key]
+		if !strings.HasPrefix(key, "$") {
+			if err := v.validateFieldCondition(key, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !topLevelOperators[key] {
+			if fieldOperators[key] {
+				return entities.NewDiagnostic(i18n.SemOperatorNeedsField, key, key)
+			}
+			return entities.NewDiagnostic(i18n.SemUnknownQueryOperator, key)
+		}
+		if err := v.validateOperand(key, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFieldCondition valida la condición de un campo. Un documento sin
+// operadores es una igualdad exacta con un subdocumento y no se revisa.
+func (v *MongoValidator) validateFieldCondition(path string, value interface{}) error {
+	document, ok := value.(map[string]interface{})
+	if !ok || !hasOperatorKeys(document) {
+		return nil
+	}
+
+	for _, key := range sortedKeys(document) {
+		operand := document[key]
+		if !strings.HasPrefix(key, "$") {
+			return entities.NewDiagnostic(i18n.SemMixedOperatorsAndFields, path)
+		}
+
+		if !fieldOperators[key] {
+			if topLevelOperators[key] {
+				return entities.NewDiagnostic(i18n.SemLogicalOperatorInField, key, path)
+			}
+			return entities.NewDiagnostic(i18n.SemUnknownQueryOperator, key)
+		}
+		if err := v.validateOperand(key, path, operand); err != nil {
+			return err
+		}
+	}
+
+	if _, hasOptions := document["$options"]; hasOptions {
+		if _, hasRegex := document["$regex"]; !hasRegex {
+			return entities.NewDiagnostic(i18n.SemOptionsWithoutRegex, path)
+		}
+	}
+
+	return nil
+}
+
+// validateOperand comprueba el tipo del operando de un operador conocido.
+// path es el campo al que se aplica (o el propio operador en la raíz).
+func (v *MongoValidator) validateOperand(operator, path string, operand interface{}) error {
+	switch operator {
+	case "$and", "$or", "$nor":
+		return v.validateLogicalOperand(operator, path, operand)
+	case "$where", "$regex":
+		return expectString(operator, path, operand)
+	case "$text", "$jsonSchema":
+		return expectDocument(operator, path, operand)
+	case "$in", "$nin", "$all":
+		return expectArray(operator, path, operand)
+	case "$exists":
+		return expectBoolean(operator, path, operand)
+	case "$type":
+		return expectBSONType(operator, path, operand)
+	case "$options":
+		return expectRegexOptions(operator, path, operand)
+	case "$elemMatch":
+		return v.validateElemMatch(operator, path, operand)
+	case "$size":
+		return expectNonNegativeInteger(operator, path, operand)
+	case "$mod":
+		return expectModOperand(operator, path, operand)
+	case "$not":
+		return v.validateNotOperand(operator, path, operand)
+	}
+
+	// $eq, $ne, $gt, $gte, $lt, $lte, $expr y $comment aceptan cualquier valor
+	return nil
+}
+
+// validateLogicalOperand exige un array no vacío de filtros y los valida
+// recursivamente.
+func (v *MongoValidator) validateLogicalOperand(operator, path string, operand interface{}) error {
+	clauses, ok := operand.([]interface{})
+	if !ok || len(clauses) == 0 {
+		return operandError(operator, path, i18n.TypeFilterArray)
+	}
+
+	for _, clause := range clauses {
+		filter, ok := clause.(map[string]interface{})
+		if !ok {
+			return operandError(operator, path, i18n.TypeFilterArray)
+		}
+		if err := v.validateFilter(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateElemMatch acepta tanto condiciones sobre los elementos
+// ({ $gt: 5 }) como un filtro sobre subdocumentos ({ campo: valor }).
+func (v *MongoValidator) validateElemMatch(operator, path string, operand interface{}) error {
+	document, ok := operand.(map[string]interface{})
+	if !ok {
+		return operandError(operator, path, i18n.TypeDocument)
+	}
+
+	for key := range document {
+		if fieldOperators[key] {
+			return v.validateFieldCondition(path, document)
+		}
+	}
+	return v.validateFilter(document)
+}
+
+// validateNotOperand exige un documento de operadores (o una expresión
+// regular, que el parser no produce) y valida sus operadores.
+func (v *MongoValidator) validateNotOperand(operator, path string, operand interface{}) error {
+	document, ok := operand.(map[string]interface{})
+	if !ok || len(document) == 0 || !hasOperatorKeys(document) {
+		return operandError(operator, path, i18n.TypeOperatorDocument)
+	}
+	return v.validateFieldCondition(path, document)
+}
+
+func expectString(operator, path string, operand interface{}) error {
+	if _, ok := operand.(string); !ok {
+		return operandError(operator, path, i18n.TypeString)
+	}
+	return nil
+}
+
+func expectDocument(operator, path string, operand interface{}) error {
+	if _, ok := operand.(map[string]interface{}); !ok {
+		return operandError(operator, path, i18n.TypeDocument)
+	}
+	return nil
+}
+
+func expectArray(operator, path string, operand interface{}) error {
+	if _, ok := operand.([]interface{}); !ok {
+		return operandError(operator, path, i18n.TypeArray)
+	}
+	return nil
+}
+
+// expectBoolean acepta también números, que MongoDB interpreta como verdadero
+// o falso.
+func expectBoolean(operator, path string, operand interface{}) error {
+	if _, ok := operand.(bool); ok || isNumber(operand) {
+		return nil
+	}
+	return operandError(operator, path, i18n.TypeBoolean)
+}
+
+func expectNonNegativeInteger(operator, path string, operand interface{}) error {
+	switch n := operand.(type) {
+	case int:
+		if n >= 0 {
+			return nil
+		}
+	case float64:
+		if n >= 0 && n == float64(int64(n)) {
+			return nil
+		}
+	}
+	return operandError(operator, path, i18n.TypeNonNegativeInteger)
+}
+
+// expectBSONType acepta un alias ("string"), un número de tipo BSON o un
+// array no vacío de ellos.
+func expectBSONType(operator, path string, operand interface{}) error {
+	types := []interface{}{operand}
+	if array, ok := operand.([]interface{}); ok && len(array) > 0 {
+		types = array
+	}
+
+	for _, bsonType := range types {
+		switch value := bsonType.(type) {
+		case string:
+			if bsonTypeAliases[value] {
+				continue
+			}
+		case int:
+			if bsonTypeCodes[value] {
+				continue
+			}
+		}
+		return operandError(operator, path, i18n.TypeBSONType)
+	}
+	return nil
+}
+
+func expectRegexOptions(operator, path string, operand interface{}) error {
+	options, ok := operand.(string)
+	if !ok || strings.Trim(options, "imsxu") != "" {
+		return operandError(operator, path, i18n.TypeRegexOptions)
+	}
+	return nil
+}
+
+// expectModOperand exige [divisor, resto] numéricos con divisor distinto de
+// cero.
+func expectModOperand(operator, path string, operand interface{}) error {
+	array, ok := operand.([]interface{})
+	if !ok || len(array) != 2 || !isNumber(array[0]) || !isNumber(array[1]) || isZero(array[0]) {
+		return operandError(operator, path, i18n.TypeModOperand)
+	}
+	return nil
+}
+
+func operandError(operator, path, expected string) error {
+	return entities.NewDiagnostic(i18n.SemOperandType, operator, path, entities.NewDiagnostic(expected))
+}
+
+func hasOperatorKeys(document map[string]interface{}) bool {
+	for key := range document {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, float64:
+		return true
+	}
+	return false
+}
+
+func isZero(value interface{}) bool {
+	switch n := value.(type) {
+	case int:
+		return n == 0
+	case float64:
+		return n == 0
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mongo-analyzer/domain/interfaces"
+)
+
+// SchemaCache guarda durante ttl los esquemas que devuelve source, de modo
+// que validar muchos documentos seguidos (una importación) no consulte el
+// servidor por cada uno. La clave es el espacio de nombres (base.coleccion).
+type SchemaCache struct {
+	source interfaces.SchemaSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedSchema
+}
+
+type cachedSchema struct {
+	schema  map[string]interface{}
+	expires time.Time
+}
+
+func NewSchemaCache(source interfaces.SchemaSource, ttl time.Duration) *SchemaCache {
+	return &SchemaCache{source: source, ttl: ttl, now: time.Now, entries: make(map[string]cachedSchema)}
+}
+
+func (c *SchemaCache) CollectionSchema(ctx context.Context, database, collection string) (map[string]interface{}, error) {
+	key := database + "." + collection
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.schema, nil
+	}
+
+	// Los errores no se guardan: la siguiente llamada lo vuelve a intentar
+	schema, err := c.source.CollectionSchema(ctx, database, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cachedSchema{schema: schema, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return schema, nil
+}
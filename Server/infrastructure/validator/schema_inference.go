@@ -0,0 +1,166 @@
+package validator
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/interfaces"
+)
+
+const (
+	// DefaultSampleSize es el número de documentos que se muestrean por
+	// colección.
+	DefaultSampleSize = 100
+	// DefaultSchemaTTL es el tiempo durante el que se reutiliza un esquema
+	// inferido.
+	DefaultSchemaTTL = 5 * time.Minute
+)
+
+// SchemaInference infiere el esquema de las colecciones a partir de una
+// muestra de documentos y lo guarda en caché durante ttl.
+type SchemaInference struct {
+	sampler    interfaces.DocumentSampler
+	sampleSize int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]*entities.InferredSchema
+}
+
+func NewSchemaInference(sampler interfaces.DocumentSampler, sampleSize int, ttl time.Duration) *SchemaInference {
+	return &SchemaInference{
+		sampler:    sampler,
+		sampleSize: sampleSize,
+		ttl:        ttl,
+		now:        time.Now,
+		cache:      make(map[string]*entities.InferredSchema),
+	}
+}
+
+// InferSchema devuelve el esquema en caché o, si ha caducado, muestrea la
+// colección de nuevo.
+func (s *SchemaInference) InferSchema(ctx context.Context, database, collection string) (*entities.InferredSchema, error) {
+	key := database + "." + collection
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.SampledAt) < s.ttl {
+		return cached, nil
+	}
+
+	documents, err := s.sampler.SampleDocuments(ctx, database, collection, s.sampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := InferSchema(documents)
+	schema.Database = database
+	schema.Collection = collection
+	schema.SampledAt = s.now()
+
+	// Una colección vacía (o que aún no existe) no se guarda: muestrearla es
+	// barato y así el primer documento que se inserte se ve enseguida.
+	if len(documents) > 0 {
+		s.mu.Lock()
+		s.cache[key] = schema
+		s.mu.Unlock()
+	}
+	return schema, nil
+}
+
+// Invalidate descarta el esquema en caché de una colección.
+func (s *SchemaInference) Invalidate(database, collection string) {
+	s.mu.Lock()
+	delete(s.cache, database+"."+collection)
+	s.mu.Unlock()
+}
+
+// InferSchema resume los campos de documents. Los subdocumentos se recorren
+// con la notación con puntos; los documentos dentro de arrays aportan sus
+// campos a la misma ruta, igual que en las consultas de MongoDB.
+func InferSchema(documents []map[string]interface{}) *entities.InferredSchema {
+	fields := make(map[string]*entities.FieldSchema)
+	field := func(path string) *entities.FieldSchema {
+		if existing, ok := fields[path]; ok {
+			return existing
+		}
+		created := &entities.FieldSchema{Path: path, Types: map[string]int{}}
+		fields[path] = created
+		return created
+	}
+
+	for _, document := range documents {
+		seen := make(map[string]bool)
+		var walk func(prefix string, document map[string]interface{})
+		walk = func(prefix string, document map[string]interface{}) {
+			for key, value := range document {
+				path := joinPath(prefix, key)
+				summary := field(path)
+				summary.Types[bsonTypeOf(value)]++
+				if !seen[path] {
+					seen[path] = true
+					summary.Count++
+				}
+
+				switch typed := value.(type) {
+				case map[string]interface{}:
+					walk(path, typed)
+				case []interface{}:
+					summary.IsArray = true
+					if summary.ElementTypes == nil {
+						summary.ElementTypes = map[string]int{}
+					}
+					for _, element := range typed {
+						summary.ElementTypes[bsonTypeOf(element)]++
+						if nested, ok := element.(map[string]interface{}); ok {
+							walk(path, nested)
+						}
+					}
+				}
+			}
+		}
+		walk("", document)
+	}
+
+	schema := &entities.InferredSchema{SampleSize: len(documents)}
+	for _, summary := range fields {
+		if len(documents) > 0 {
+			summary.Frequency = float64(summary.Count) / float64(len(documents))
+		}
+		schema.Fields = append(schema.Fields, *summary)
+	}
+	sort.Slice(schema.Fields, func(i, j int) bool { return schema.Fields[i].Path < schema.Fields[j].Path })
+	return schema
+}
+
+// knownPath indica si la ruta aparece en el esquema. Los índices de array
+// (items.0.sku) se ignoran.
+func knownPath(schema *entities.InferredSchema, path string) bool {
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		if !isArrayIndex(segment) {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return true
+	}
+	_, ok := schema.Field(strings.Join(segments, "."))
+	return ok
+}
+
+// typeFamily agrupa los tipos que MongoDB compara entre sí: todos los
+// numéricos pertenecen a la misma familia.
+func typeFamily(bsonType string) string {
+	switch bsonType {
+	case "int", "long", "double", "decimal":
+		return "number"
+	}
+	return bsonType
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"context"
+	"sync"
+
+	"mongo-analyzer/domain/interfaces"
+)
+
+// SchemaRegistry guarda los $jsonSchema registrados por espacio de nombres
+// (base.coleccion). Si una colección no tiene esquema registrado se consulta
+// fallback (normalmente el validador que la colección tiene en el servidor).
+type SchemaRegistry struct {
+	mu       sync.RWMutex
+	schemas  map[string]map[string]interface{}
+	fallback interfaces.SchemaSource
+}
+
+func NewSchemaRegistry(fallback interfaces.SchemaSource) *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas:  make(map[string]map[string]interface{}),
+		fallback: fallback,
+	}
+}
+
+// Register asocia un esquema a una colección de una base de datos. Acepta
+// tanto el esquema como el validador completo ({ $jsonSchema: { ... } }).
+func (r *SchemaRegistry) Register(database, collection string, schema map[string]interface{}) {
+	if inner, ok := schema["$jsonSchema"].(map[string]interface{}); ok && len(schema) == 1 {
+		schema = inner
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[database+"."+collection] = schema
+}
+
+// Unregister elimina el esquema registrado de una colección.
+func (r *SchemaRegistry) Unregister(database, collection string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := database + "." + collection
+	_, ok := r.schemas[key]
+	delete(r.schemas, key)
+	return ok
+}
+
+// Registered devuelve el esquema registrado de una colección, sin consultar
+// el servidor.
+func (r *SchemaRegistry) Registered(database, collection string) (map[string]interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[database+"."+collection]
+	return schema, ok
+}
+
+// CollectionSchema devuelve el esquema registrado o, si no lo hay, el de
+// fallback.
+func (r *SchemaRegistry) CollectionSchema(ctx context.Context, database, collection string) (map[string]interface{}, error) {
+	if schema, ok := r.Registered(database, collection); ok {
+		return schema, nil
+	}
+	if r.fallback == nil {
+		return nil, nil
+	}
+	return r.fallback.CollectionSchema(ctx, database, collection)
+}
@@ -0,0 +1,254 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+// Modificadores admitidos dentro de $push (y $each también en $addToSet).
+var pushModifiers = map[string]bool{
+	"$each": true, "$slice": true, "$sort": true, "$position": true,
+}
+
+// Etapas permitidas en una actualización estilo pipeline.
+var updatePipelineStages = map[string]bool{
+	"$addFields": true, "$set": true, "$project": true,
+	"$unset": true, "$replaceRoot": true, "$replaceWith": true,
+}
+
+// validateUpdateDocument comprueba un documento de actualización con
+// operadores: que todos sean conocidos, que no se mezclen con campos, que
+// los operandos tengan el tipo correcto y que no haya rutas en conflicto.
+func (v *MongoValidator) validateUpdateDocument(update map[string]interface{}) error {
+	if !hasOperatorKeys(update) {
+		return entities.NewDiagnostic(i18n.SemUpdateNoOperator)
+	}
+	if err := validateNesting(update); err != nil {
+		return err
+	}
+
+	var paths []string
+	for _, operator := range sortedKeys(update) {
+		if !strings.HasPrefix(operator, "$") {
+			return entities.NewDiagnostic(i18n.SemUpdateMixedFields, operator)
+		}
+		if !isUpdateOperator(operator) {
+			return entities.NewDiagnostic(i18n.SemUnknownUpdateOperator, operator)
+		}
+
+		fields, ok := update[operator].(map[string]interface{})
+		if !ok || len(fields) == 0 {
+			return entities.NewDiagnostic(i18n.SemUpdateOperatorEmpty, operator)
+		}
+
+		for _, path := range sortedKeys(fields) {
+			if err := validateFieldPath(path); err != nil {
+				return err
+			}
+			if err := v.validateUpdateOperand(operator, path, fields[path]); err != nil {
+				return err
+			}
+			paths = append(paths, path)
+			if operator == "$rename" {
+				paths = append(paths, fields[path].(string))
+			}
+		}
+	}
+
+	return validateUpdatePaths(paths)
+}
+
+// validateUpdateOperand comprueba el valor que un operador de actualización
+// asigna a un campo.
+func (v *MongoValidator) validateUpdateOperand(operator, path string, operand interface{}) error {
+	switch operator {
+	case "$inc", "$mul":
+		if !isNumber(operand) {
+			return operandError(operator, path, i18n.TypeNumber)
+		}
+	case "$rename":
+		target, ok := operand.(string)
+		if !ok || target == "" || target == path {
+			return operandError(operator, path, i18n.TypeRenameTarget)
+		}
+		return validateFieldPath(target)
+	case "$currentDate":
+		if !isCurrentDateSpec(operand) {
+			return operandError(operator, path, i18n.TypeCurrentDate)
+		}
+	case "$pop":
+		if n, ok := operand.(int); !ok || (n != 1 && n != -1) {
+			return operandError(operator, path, i18n.TypePopDirection)
+		}
+	case "$pullAll":
+		return expectArray(operator, path, operand)
+	case "$pull":
+		return v.validateFieldCondition(path, operand)
+	case "$push", "$addToSet":
+		return validateArrayModifiers(operator, path, operand)
+	}
+
+	// $set, $setOnInsert, $unset, $min y $max aceptan cualquier valor
+	return nil
+}
+
+// validateArrayModifiers valida { $each: [...], $slice, $sort, $position }
+// cuando el valor de $push o $addToSet es un documento de modificadores.
+func validateArrayModifiers(operator, path string, operand interface{}) error {
+	modifiers, ok := operand.(map[string]interface{})
+	if !ok || !hasOperatorKeys(modifiers) {
+		return nil
+	}
+
+	for _, modifier := range sortedKeys(modifiers) {
+		if !pushModifiers[modifier] || (operator == "$addToSet" && modifier != "$each") {
+			return entities.NewDiagnostic(i18n.SemUnknownUpdateModifier, modifier, operator)
+		}
+	}
+
+	each, ok := modifiers["$each"]
+	if !ok {
+		return entities.NewDiagnostic(i18n.SemUpdateModifierWithoutEach, path)
+	}
+	if err := expectArray("$each", path, each); err != nil {
+		return err
+	}
+
+	if slice, ok := modifiers["$slice"]; ok {
+		if _, isInt := slice.(int); !isInt {
+			return operandError("$slice", path, i18n.TypeInteger)
+		}
+	}
+	if position, ok := modifiers["$position"]; ok {
+		if _, isInt := position.(int); !isInt {
+			return operandError("$position", path, i18n.TypeInteger)
+		}
+	}
+	if order, ok := modifiers["$sort"]; ok && !isSortSpec(order) {
+		return operandError("$sort", path, i18n.TypeSortSpec)
+	}
+
+	return nil
+}
+
+// validateUpdatePipeline comprueba una actualización estilo pipeline: un
+// array no vacío de etapas con un único operador permitido cada una.
+func (v *MongoValidator) validateUpdatePipeline(pipeline []interface{}) error {
+	if len(pipeline) == 0 {
+		return entities.NewDiagnostic(i18n.SemUpdatePipelineEmpty)
+	}
+	if err := validateNesting(pipeline); err != nil {
+		return err
+	}
+
+	for _, element := range pipeline {
+		stage, ok := element.(map[string]interface{})
+		if !ok || len(stage) != 1 {
+			return entities.NewDiagnostic(i18n.SemUpdatePipelineStageShape)
+		}
+
+		for name, operand := range stage {
+			if !updatePipelineStages[name] {
+				return entities.NewDiagnostic(i18n.SemUpdatePipelineStage, name)
+			}
+
+			switch name {
+			case "$unset":
+				if !isStringOrStringArray(operand) {
+					return operandError(name, name, i18n.TypeFieldList)
+				}
+			case "$replaceWith":
+				// acepta una expresión cualquiera ("$campo" o un documento)
+			default:
+				if err := expectDocument(name, name, operand); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateUpdatePaths detecta rutas que se modifican a la vez, ya sea la
+// misma ruta o una contenida en otra ("a" y "a.b").
+func validateUpdatePaths(paths []string) error {
+	for i, first := range paths {
+		for _, second := range paths[i+1:] {
+			if first == second || strings.HasPrefix(first, second+".") || strings.HasPrefix(second, first+".") {
+				return entities.NewDiagnostic(i18n.SemUpdatePathConflict, first, second)
+			}
+		}
+	}
+	return nil
+}
+
+func isUpdateOperator(operator string) bool {
+	if pushModifiers[operator] {
+		return false
+	}
+	for _, known := range entities.UpdateOperators {
+		if known == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// isCurrentDateSpec acepta true o { $type: "date" | "timestamp" }.
+func isCurrentDateSpec(operand interface{}) bool {
+	switch value := operand.(type) {
+	case bool:
+		return value
+	case map[string]interface{}:
+		kind, ok := value["$type"].(string)
+		return len(value) == 1 && ok && (kind == "date" || kind == "timestamp")
+	}
+	return false
+}
+
+// isSortSpec acepta 1, -1 o un documento { campo: 1 | -1 }.
+func isSortSpec(operand interface{}) bool {
+	switch value := operand.(type) {
+	case int:
+		return value == 1 || value == -1
+	case map[string]interface{}:
+		if len(value) == 0 {
+			return false
+		}
+		for _, direction := range value {
+			if n, ok := direction.(int); !ok || (n != 1 && n != -1) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func isStringOrStringArray(operand interface{}) bool {
+	switch value := operand.(type) {
+	case string:
+		return true
+	case []interface{}:
+		for _, element := range value {
+			if _, ok := element.(string); !ok {
+				return false
+			}
+		}
+		return len(value) > 0
+	}
+	return false
+}
+
+func sortedKeys(document map[string]interface{}) []string {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"mongo-analyzer/domain/entities"
+	"mongo-analyzer/domain/i18n"
+)
+
+type TraceToken struct {
+	Type   string `json:"type"`
+	Lexeme string `json:"lexeme"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type TraceNode struct {
+	Kind     string       `json:"kind"`
+	Lexeme   string       `json:"lexeme,omitempty"`
+	Line     int          `json:"line,omitempty"`
+	Column   int          `json:"column,omitempty"`
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+type TraceCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+type TraceResponse struct {
+	Tokens    []TraceToken       `json:"tokens"`
+	AST       *TraceNode         `json:"ast,omitempty"`
+	Checks    []TraceCheck       `json:"checks"`
+	TimingsMs map[string]float64 `json:"timings_ms"`
+}
+
+func newTraceResponse(trace *entities.AnalysisTrace, locale string) *TraceResponse {
+	if trace == nil {
+		return nil
+	}
+
+	response := &TraceResponse{
+		Tokens:    make([]TraceToken, 0, len(trace.Tokens)),
+		AST:       newTraceNode(trace.AST),
+		Checks:    make([]TraceCheck, 0, len(trace.Checks)),
+		TimingsMs: make(map[string]float64, len(trace.Timings)),
+	}
+
+	for _, token := range trace.Tokens {
+		response.Tokens = append(response.Tokens, TraceToken{
+			Type:   token.Type.String(),
+			Lexeme: token.Value,
+			Line:   token.Line,
+			Column: token.Column,
+		})
+	}
+
+	for _, check := range trace.Checks {
+		response.Checks = append(response.Checks, TraceCheck{
+			Name:   check.Name,
+			Passed: check.Error == nil,
+			Error:  i18n.LocalizeError(check.Error, locale),
+		})
+	}
+
+	for _, timing := range trace.Timings {
+		response.TimingsMs[timing.Phase] = float64(timing.Duration.Microseconds()) / 1000
+	}
+
+	return response
+}
+
+func newTraceNode(node *entities.ASTNode) *TraceNode {
+	if node == nil {
+		return nil
+	}
+
+	traceNode := &TraceNode{Kind: node.Kind}
+	if node.IsLeaf() {
+		traceNode.Lexeme = node.Token.Value
+		traceNode.Line = node.Token.Line
+		traceNode.Column = node.Token.Column
+	}
+	for _, child := range node.Children {
+		traceNode.Children = append(traceNode.Children, newTraceNode(child))
+	}
+	return traceNode
+}
+
+type DerivationStep struct {
+	Step       int         `json:"step"`
+	Action     string      `json:"action"`
+	Production string      `json:"production"`
+	Function   string      `json:"function"`
+	Depth      int         `json:"depth"`
+	Token      *TraceToken `json:"token,omitempty"`
+	Branch     string      `json:"branch,omitempty"`
+}
+
+func newDerivationResponse(log *entities.DerivationLog) []DerivationStep {
+	if log == nil {
+		return nil
+	}
+
+	steps := make([]DerivationStep, 0, len(log.Steps))
+	for i, step := range log.Steps {
+		response := DerivationStep{
+			Step:       i + 1,
+			Action:     string(step.Action),
+			Production: step.Production,
+			Function:   step.Function,
+			Depth:      step.Depth,
+			Branch:     step.Branch,
+		}
+		if step.Token != nil {
+			response.Token = &TraceToken{
+				Type:   step.Token.Type.String(),
+				Lexeme: step.Token.Value,
+				Line:   step.Token.Line,
+				Column: step.Token.Column,
+			}
+		}
+		steps = append(steps, response)
+	}
+	return steps
+}